@@ -0,0 +1,54 @@
+/****************************************************************************
+ * Copyright 2026, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package client has client definitions
+package client
+
+import (
+	"github.com/WolffunService/experiment/pkg/decision"
+	"github.com/WolffunService/experiment/pkg/entities"
+	"github.com/WolffunService/experiment/pkg/event"
+)
+
+// banditEventProcessor records an exposure in the bandit store for every impression event the wrapped processor
+// accepts, so that decisions made without an impression event are not counted as exposures
+type banditEventProcessor struct {
+	event.Processor
+	banditStore decision.BanditStore
+}
+
+// ProcessEvent processes the event with the wrapped processor and records the exposure of bandit impressions
+func (p *banditEventProcessor) ProcessEvent(userEvent event.UserEvent) bool {
+	if !p.Processor.ProcessEvent(userEvent) {
+		return false
+	}
+	impression := userEvent.Impression
+	if impression == nil || impression.ExperimentID == "" {
+		return true
+	}
+
+	// the bucketing ID is sent as a visitor attribute when it differs from the user ID
+	attributes := make(map[string]interface{}, len(impression.Attributes))
+	for _, attribute := range impression.Attributes {
+		attributes[attribute.Key] = attribute.Value
+	}
+	bucketingID, _ := entities.UserContext{ID: userEvent.VisitorID, Attributes: attributes}.GetBucketingID()
+	// forced and whitelisted variations are not allocated by the bandit
+	if variationID, ok := p.banditStore.GetAssignment(impression.ExperimentID, bucketingID); ok && variationID == impression.VariationID {
+		p.banditStore.RecordExposure(impression.ExperimentID, bucketingID)
+	}
+	return true
+}
@@ -40,13 +40,14 @@ import (
 
 // OptimizelyClient is the entry point to the Optimizely SDK
 type OptimizelyClient struct {
-	ConfigManager        config.ProjectConfigManager
-	DecisionService      decision.Service
-	EventProcessor       event.Processor
-	notificationCenter   notification.Center
-	execGroup            *utils.ExecGroup
-	logger               logging.OptimizelyLogProducer
-	defaultDecideOptions *decide.Options
+	ConfigManager          config.ProjectConfigManager
+	DecisionService        decision.Service
+	EventProcessor         event.Processor
+	notificationCenter     notification.Center
+	execGroup              *utils.ExecGroup
+	logger                 logging.OptimizelyLogProducer
+	defaultDecideOptions   *decide.Options
+	ownsNotificationCenter bool // the notification center was created for the client and is cleared on Close
 }
 
 // CreateUserContext creates a context of the user for which decision APIs will be called.
@@ -971,6 +972,14 @@ func (o *OptimizelyClient) GetOptimizelyConfig() (optimizelyConfig *config.Optim
 // Close closes the Optimizely instance and stops any ongoing tasks from its children components.
 func (o *OptimizelyClient) Close() {
 	o.execGroup.TerminateAndWait()
+	if center, ok := o.notificationCenter.(*notification.DefaultCenter); ok && o.ownsNotificationCenter {
+		center.Clear()
+	}
+}
+
+// GetNotificationCenter returns the notification center of the client
+func (o *OptimizelyClient) GetNotificationCenter() notification.Center {
+	return o.notificationCenter
 }
 
 func (o *OptimizelyClient) getDecisionVariableMap(feature entities.Feature, variation *entities.Variation, featureEnabled bool) (map[string]interface{}, decide.DecisionReasons) {
@@ -0,0 +1,342 @@
+/****************************************************************************
+ * Copyright 2026, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package client has client definitions
+package client
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/WolffunService/experiment/pkg/event"
+	"github.com/WolffunService/experiment/pkg/logging"
+	"github.com/WolffunService/experiment/pkg/registry"
+	"github.com/WolffunService/experiment/pkg/utils"
+)
+
+// minIdleCheckInterval bounds how often Start looks for idle clients
+const minIdleCheckInterval = time.Second
+
+// ClientManager owns one OptimizelyClient per SDK key, for instance one per environment or per project. Clients are
+// created on first use and, when a maximum number of clients or an idle timeout is set, evicted and closed when least
+// recently used or idle. Besides its own notification center, the notification center the registry holds for the SDK
+// key of an evicted client is removed, for the components created outside of the factory which still use it.
+type ClientManager struct {
+	clientOptions     []OptionFunc
+	sdkKeyOptions     map[string][]OptionFunc
+	eventDispatcher   event.Dispatcher
+	datafileRequester utils.Requester
+	maxClients        int
+	idleTimeout       time.Duration
+	logger            logging.OptimizelyLogProducer
+	now               func() time.Time
+
+	lock    sync.Mutex
+	clients map[string]*list.Element
+	pending map[string]*pendingClient // clients being created, by SDK key
+	lru     *list.List                // most recently used first
+	closed  bool
+}
+
+// pendingClient is a client being created, done is closed once client or err is set
+type pendingClient struct {
+	done   chan struct{}
+	client *OptimizelyClient
+	err    error
+}
+
+type managedClient struct {
+	sdkKey   string
+	client   *OptimizelyClient
+	lastUsed time.Time
+}
+
+// ClientHealth is the health of a client owned by a ClientManager
+type ClientHealth struct {
+	SDKKey   string
+	Ready    bool      // whether the client has a valid config
+	Revision string    // the revision of the config, empty if not ready
+	Err      error     // the error of the config manager, if any
+	LastUsed time.Time // the last time the client was requested from the manager
+}
+
+// ClientManagerHealth is the health of all the clients owned by a ClientManager
+type ClientManagerHealth struct {
+	Healthy bool // whether every client is ready
+	Clients []ClientHealth
+}
+
+// ClientManagerOptionFunc is used to provide custom configuration to the ClientManager.
+type ClientManagerOptionFunc func(*ClientManager)
+
+// WithClientOptions sets options applied to every client created by the manager
+func WithClientOptions(options ...OptionFunc) ClientManagerOptionFunc {
+	return func(m *ClientManager) {
+		m.clientOptions = append(m.clientOptions, options...)
+	}
+}
+
+// WithSDKKeyClientOptions sets options applied to the client of the given SDK key, after the options of all clients
+func WithSDKKeyClientOptions(sdkKey string, options ...OptionFunc) ClientManagerOptionFunc {
+	return func(m *ClientManager) {
+		m.sdkKeyOptions[sdkKey] = append(m.sdkKeyOptions[sdkKey], options...)
+	}
+}
+
+// WithSharedEventDispatcher sets an event dispatcher shared by all the clients, it is not closed with the clients
+func WithSharedEventDispatcher(dispatcher event.Dispatcher) ClientManagerOptionFunc {
+	return func(m *ClientManager) {
+		m.eventDispatcher = dispatcher
+	}
+}
+
+// WithSharedDatafileRequester sets a requester all the clients fetch their datafile with
+func WithSharedDatafileRequester(requester utils.Requester) ClientManagerOptionFunc {
+	return func(m *ClientManager) {
+		m.datafileRequester = requester
+	}
+}
+
+// WithMaxClients sets the maximum number of clients, the least recently used client is closed to make room for a new one
+func WithMaxClients(maxClients int) ClientManagerOptionFunc {
+	return func(m *ClientManager) {
+		m.maxClients = maxClients
+	}
+}
+
+// WithIdleTimeout sets how long a client may go unused before it is closed by EvictIdle
+func WithIdleTimeout(idleTimeout time.Duration) ClientManagerOptionFunc {
+	return func(m *ClientManager) {
+		m.idleTimeout = idleTimeout
+	}
+}
+
+// NewClientManager returns a client manager with the given options, without limits on the number of clients
+func NewClientManager(options ...ClientManagerOptionFunc) *ClientManager {
+	m := &ClientManager{
+		sdkKeyOptions: map[string][]OptionFunc{},
+		logger:        logging.GetLogger("", "ClientManager"),
+		now:           time.Now,
+		clients:       map[string]*list.Element{},
+		pending:       map[string]*pendingClient{},
+		lru:           list.New(),
+	}
+	for _, opt := range options {
+		opt(m)
+	}
+	return m
+}
+
+// Client returns the client of the given SDK key, creating it on first use. Clients are created outside of the lock of
+// the manager, so options which block, such as a static config manager fetching its datafile, only delay the callers
+// asking for the same SDK key, which wait for and share the client being created.
+func (m *ClientManager) Client(sdkKey string) (*OptimizelyClient, error) {
+	m.lock.Lock()
+	if m.closed {
+		m.lock.Unlock()
+		return nil, fmt.Errorf("client manager is closed")
+	}
+	if element, ok := m.clients[sdkKey]; ok {
+		managed := element.Value.(*managedClient)
+		managed.lastUsed = m.now()
+		m.lru.MoveToFront(element)
+		m.lock.Unlock()
+		return managed.client, nil
+	}
+	if pending, ok := m.pending[sdkKey]; ok {
+		m.lock.Unlock()
+		<-pending.done
+		return pending.client, pending.err
+	}
+	pending := &pendingClient{done: make(chan struct{})}
+	m.pending[sdkKey] = pending
+	m.lock.Unlock()
+
+	pending.client, pending.err = m.newClient(sdkKey)
+	defer close(pending.done)
+
+	m.lock.Lock()
+	delete(m.pending, sdkKey)
+	if pending.err != nil {
+		m.lock.Unlock()
+		return nil, pending.err
+	}
+	if m.closed {
+		m.lock.Unlock()
+		pending.client.Close()
+		pending.client, pending.err = nil, fmt.Errorf("client manager is closed")
+		return nil, pending.err
+	}
+	m.clients[sdkKey] = m.lru.PushFront(&managedClient{sdkKey: sdkKey, client: pending.client, lastUsed: m.now()})
+
+	var evicted []*managedClient
+	for m.maxClients > 0 && m.lru.Len() > m.maxClients {
+		evicted = append(evicted, m.remove(m.lru.Back()))
+	}
+	m.lock.Unlock()
+
+	m.closeClients(evicted, "least recently used")
+	return pending.client, nil
+}
+
+func (m *ClientManager) newClient(sdkKey string) (*OptimizelyClient, error) {
+	var options []OptionFunc
+	if m.eventDispatcher != nil {
+		options = append(options, WithEventDispatcher(m.eventDispatcher))
+	}
+	if m.datafileRequester != nil {
+		options = append(options, WithDatafileRequester(m.datafileRequester))
+	}
+	options = append(options, m.clientOptions...)
+	options = append(options, m.sdkKeyOptions[sdkKey]...)
+
+	factory := &OptimizelyFactory{SDKKey: sdkKey}
+	return factory.Client(options...)
+}
+
+// Remove closes the client of the given SDK key, it returns false if the manager has no such client
+func (m *ClientManager) Remove(sdkKey string) bool {
+	m.lock.Lock()
+	element, ok := m.clients[sdkKey]
+	var removed *managedClient
+	if ok {
+		removed = m.remove(element)
+	}
+	m.lock.Unlock()
+
+	if ok {
+		m.closeClients([]*managedClient{removed}, "removed")
+	}
+	return ok
+}
+
+// EvictIdle closes the clients which were not used for longer than the idle timeout and returns their SDK keys
+func (m *ClientManager) EvictIdle() []string {
+	if m.idleTimeout <= 0 {
+		return nil
+	}
+
+	m.lock.Lock()
+	var evicted []*managedClient
+	deadline := m.now().Add(-m.idleTimeout)
+	for element := m.lru.Back(); element != nil; element = m.lru.Back() {
+		if element.Value.(*managedClient).lastUsed.After(deadline) {
+			break
+		}
+		evicted = append(evicted, m.remove(element))
+	}
+	m.lock.Unlock()
+
+	m.closeClients(evicted, "idle")
+	sdkKeys := make([]string, len(evicted))
+	for i, managed := range evicted {
+		sdkKeys[i] = managed.sdkKey
+	}
+	return sdkKeys
+}
+
+// Start evicts idle clients periodically until the context is done, it returns right away without an idle timeout
+func (m *ClientManager) Start(ctx context.Context) {
+	if m.idleTimeout <= 0 {
+		return
+	}
+	interval := m.idleTimeout / 2
+	if interval < minIdleCheckInterval {
+		interval = minIdleCheckInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			m.EvictIdle()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// SDKKeys returns the SDK keys of the clients owned by the manager, most recently used first
+func (m *ClientManager) SDKKeys() []string {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	sdkKeys := make([]string, 0, m.lru.Len())
+	for element := m.lru.Front(); element != nil; element = element.Next() {
+		sdkKeys = append(sdkKeys, element.Value.(*managedClient).sdkKey)
+	}
+	return sdkKeys
+}
+
+// Health reports whether each client has a valid config, sorted by SDK key
+func (m *ClientManager) Health() ClientManagerHealth {
+	m.lock.Lock()
+	managedClients := make([]managedClient, 0, m.lru.Len())
+	for element := m.lru.Front(); element != nil; element = element.Next() {
+		managedClients = append(managedClients, *element.Value.(*managedClient))
+	}
+	m.lock.Unlock()
+
+	health := ClientManagerHealth{Healthy: true, Clients: []ClientHealth{}}
+	for _, managed := range managedClients {
+		clientHealth := ClientHealth{SDKKey: managed.sdkKey, LastUsed: managed.lastUsed}
+		projectConfig, err := managed.client.getProjectConfig()
+		if err == nil && projectConfig != nil {
+			clientHealth.Ready = true
+			clientHealth.Revision = projectConfig.GetRevision()
+		} else {
+			clientHealth.Err = err
+			health.Healthy = false
+		}
+		health.Clients = append(health.Clients, clientHealth)
+	}
+	sort.Slice(health.Clients, func(i, j int) bool {
+		return health.Clients[i].SDKKey < health.Clients[j].SDKKey
+	})
+	return health
+}
+
+// Close closes all the clients, the manager does not create clients anymore
+func (m *ClientManager) Close() {
+	m.lock.Lock()
+	m.closed = true
+	var closed []*managedClient
+	for element := m.lru.Front(); element != nil; element = m.lru.Front() {
+		closed = append(closed, m.remove(element))
+	}
+	m.lock.Unlock()
+
+	m.closeClients(closed, "closed")
+}
+
+// remove must be called with the lock held, the notification center is removed right away so that a client created
+// again for the same SDK key does not share it with the client being closed
+func (m *ClientManager) remove(element *list.Element) *managedClient {
+	managed := m.lru.Remove(element).(*managedClient)
+	delete(m.clients, managed.sdkKey)
+	registry.RemoveNotificationCenter(managed.sdkKey)
+	return managed
+}
+
+func (m *ClientManager) closeClients(managedClients []*managedClient, reason string) {
+	for _, managed := range managedClients {
+		m.logger.Debug(fmt.Sprintf("Closing client of SDK key %s: %s", managed.sdkKey, reason))
+		managed.client.Close()
+	}
+}
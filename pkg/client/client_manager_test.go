@@ -0,0 +1,231 @@
+/****************************************************************************
+ * Copyright 2026, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/WolffunService/experiment/pkg/config"
+	"github.com/WolffunService/experiment/pkg/event"
+	"github.com/WolffunService/experiment/pkg/registry"
+	"github.com/WolffunService/experiment/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// sdkKeyRequester serves a datafile whose revision is the SDK key in the URL, or a 403 for keys starting with forbidden
+type sdkKeyRequester struct {
+	utils.Requester
+	lock sync.Mutex
+	urls []string
+}
+
+func (r *sdkKeyRequester) Get(url string, headers ...utils.Header) ([]byte, http.Header, int, error) {
+	r.lock.Lock()
+	r.urls = append(r.urls, url)
+	r.lock.Unlock()
+	sdkKey := strings.TrimSuffix(url[strings.LastIndex(url, "/")+1:], ".json")
+	if strings.HasPrefix(sdkKey, "forbidden") {
+		return nil, http.Header{}, http.StatusForbidden, fmt.Errorf("forbidden")
+	}
+	return []byte(fmt.Sprintf(`{"revision":"%s","version":"4"}`, sdkKey)), http.Header{}, http.StatusOK, nil
+}
+
+// closeSignal returns a channel which is closed when the client is closed
+func closeSignal(client *OptimizelyClient) <-chan struct{} {
+	closed := make(chan struct{})
+	client.execGroup.Go(func(ctx context.Context) {
+		<-ctx.Done()
+		close(closed)
+	})
+	return closed
+}
+
+func assertClosed(t *testing.T, closed <-chan struct{}) {
+	select {
+	case <-closed:
+	default:
+		assert.Fail(t, "client is not closed")
+	}
+}
+
+func waitForReady(t *testing.T, client *OptimizelyClient) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, client.WaitForReady(ctx))
+}
+
+func TestClientManagerCreatesClientsLazily(t *testing.T) {
+	requester := &sdkKeyRequester{}
+	dispatcher := &MockDispatcher{}
+	manager := NewClientManager(WithSharedDatafileRequester(requester), WithSharedEventDispatcher(dispatcher))
+	defer manager.Close()
+	assert.Empty(t, manager.SDKKeys())
+
+	dev, err := manager.Client("manager_dev")
+	assert.NoError(t, err)
+	again, err := manager.Client("manager_dev")
+	assert.NoError(t, err)
+	assert.True(t, dev == again)
+	prod, err := manager.Client("manager_prod")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"manager_prod", "manager_dev"}, manager.SDKKeys())
+
+	waitForReady(t, dev)
+	waitForReady(t, prod)
+	assert.Equal(t, "manager_prod", prod.GetOptimizelyConfig().Revision)
+	assert.Contains(t, requester.urls, fmt.Sprintf(config.DatafileURLTemplate, "manager_dev"))
+	assert.Contains(t, requester.urls, fmt.Sprintf(config.DatafileURLTemplate, "manager_prod"))
+	for _, client := range []*OptimizelyClient{dev, prod} {
+		assert.True(t, client.EventProcessor.(*event.BatchEventProcessor).EventDispatcher == dispatcher)
+	}
+}
+
+func TestClientManagerCreatesClientsOutsideOfTheLock(t *testing.T) {
+	created := make(chan struct{}, 2)
+	release := make(chan struct{})
+	blockingOption := func(f *OptimizelyFactory) {
+		created <- struct{}{}
+		<-release
+	}
+	manager := NewClientManager(WithSharedDatafileRequester(&sdkKeyRequester{}), WithSDKKeyClientOptions("manager_slow", blockingOption))
+	defer manager.Close()
+
+	clients := make(chan *OptimizelyClient, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			client, err := manager.Client("manager_slow")
+			assert.NoError(t, err)
+			clients <- client
+		}()
+	}
+	<-created
+
+	// other SDK keys are not held up by the client being created
+	_, err := manager.Client("manager_fast")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"manager_fast"}, manager.SDKKeys())
+
+	close(release)
+	first, second := <-clients, <-clients
+	assert.True(t, first == second)
+	assert.Len(t, created, 0)
+	assert.Equal(t, []string{"manager_slow", "manager_fast"}, manager.SDKKeys())
+}
+
+func TestClientManagerAppliesClientOptions(t *testing.T) {
+	staticManager := config.NewStaticProjectConfigManagerWithOptions("", config.WithInitialDatafile([]byte(`{"revision":"static","version":"4"}`)))
+	manager := NewClientManager(
+		WithSharedDatafileRequester(&sdkKeyRequester{}),
+		WithClientOptions(WithDefaultDecideOptions(nil)),
+		WithSDKKeyClientOptions("manager_static", WithConfigManager(staticManager)),
+	)
+	defer manager.Close()
+
+	client, err := manager.Client("manager_static")
+	assert.NoError(t, err)
+	assert.True(t, client.ConfigManager == staticManager)
+	client, err = manager.Client("manager_other")
+	assert.NoError(t, err)
+	assert.IsType(t, &config.PollingProjectConfigManager{}, client.ConfigManager)
+}
+
+func TestClientManagerEvictsLeastRecentlyUsed(t *testing.T) {
+	manager := NewClientManager(WithSharedDatafileRequester(&sdkKeyRequester{}), WithMaxClients(2))
+	defer manager.Close()
+
+	first, _ := manager.Client("manager_lru_1")
+	firstClosed := closeSignal(first)
+	_, _ = manager.Client("manager_lru_2")
+	_, _ = manager.Client("manager_lru_1")
+	_, _ = manager.Client("manager_lru_3")
+	assert.Equal(t, []string{"manager_lru_3", "manager_lru_1"}, manager.SDKKeys())
+
+	// the evicted client is closed and its notification center is removed from the registry
+	manager.Client("manager_lru_4")
+	assert.Equal(t, []string{"manager_lru_4", "manager_lru_3"}, manager.SDKKeys())
+	assertClosed(t, firstClosed)
+	assert.True(t, first.notificationCenter != registry.GetNotificationCenter("manager_lru_1"))
+
+	recreated, _ := manager.Client("manager_lru_1")
+	assert.True(t, first != recreated)
+}
+
+func TestClientManagerEvictsIdleClients(t *testing.T) {
+	now := time.Now()
+	manager := NewClientManager(WithSharedDatafileRequester(&sdkKeyRequester{}), WithIdleTimeout(time.Minute))
+	manager.now = func() time.Time { return now }
+	defer manager.Close()
+
+	manager.Client("manager_idle_1")
+	now = now.Add(40 * time.Second)
+	manager.Client("manager_idle_2")
+	assert.Empty(t, manager.EvictIdle())
+
+	now = now.Add(30 * time.Second)
+	assert.Equal(t, []string{"manager_idle_1"}, manager.EvictIdle())
+	assert.Equal(t, []string{"manager_idle_2"}, manager.SDKKeys())
+
+	assert.Empty(t, NewClientManager().EvictIdle())
+}
+
+func TestClientManagerRemoveAndClose(t *testing.T) {
+	manager := NewClientManager(WithSharedDatafileRequester(&sdkKeyRequester{}))
+	client, _ := manager.Client("manager_remove_1")
+	closed := closeSignal(client)
+	manager.Client("manager_remove_2")
+
+	assert.True(t, manager.Remove("manager_remove_1"))
+	assert.False(t, manager.Remove("manager_remove_1"))
+	assertClosed(t, closed)
+	assert.Equal(t, []string{"manager_remove_2"}, manager.SDKKeys())
+
+	manager.Close()
+	assert.Empty(t, manager.SDKKeys())
+	_, err := manager.Client("manager_remove_3")
+	assert.EqualError(t, err, "client manager is closed")
+}
+
+func TestClientManagerHealth(t *testing.T) {
+	manager := NewClientManager(WithSharedDatafileRequester(&sdkKeyRequester{}))
+	defer manager.Close()
+	assert.Equal(t, ClientManagerHealth{Healthy: true, Clients: []ClientHealth{}}, manager.Health())
+
+	client, _ := manager.Client("manager_health_ok")
+	waitForReady(t, client)
+	forbidden, _ := manager.Client("forbidden_manager_health")
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.Equal(t, config.Err403Forbidden, forbidden.WaitForReady(ctx))
+
+	health := manager.Health()
+	assert.False(t, health.Healthy)
+	if assert.Len(t, health.Clients, 2) {
+		assert.Equal(t, "forbidden_manager_health", health.Clients[0].SDKKey)
+		assert.False(t, health.Clients[0].Ready)
+		assert.Equal(t, config.Err403Forbidden, health.Clients[0].Err)
+		assert.Equal(t, "manager_health_ok", health.Clients[1].SDKKey)
+		assert.True(t, health.Clients[1].Ready)
+		assert.Equal(t, "manager_health_ok", health.Clients[1].Revision)
+	}
+}
@@ -25,10 +25,11 @@ import (
 	"github.com/WolffunService/experiment/pkg/config"
 	"github.com/WolffunService/experiment/pkg/decide"
 	"github.com/WolffunService/experiment/pkg/decision"
+	"github.com/WolffunService/experiment/pkg/decision/bucketer"
 	"github.com/WolffunService/experiment/pkg/event"
 	"github.com/WolffunService/experiment/pkg/logging"
 	"github.com/WolffunService/experiment/pkg/metrics"
-	"github.com/WolffunService/experiment/pkg/registry"
+	"github.com/WolffunService/experiment/pkg/notification"
 	"github.com/WolffunService/experiment/pkg/utils"
 )
 
@@ -38,15 +39,24 @@ type OptimizelyFactory struct {
 	Datafile            []byte
 	DatafileAccessToken string
 
-	configManager        config.ProjectConfigManager
-	ctx                  context.Context
-	decisionService      decision.Service
-	defaultDecideOptions *decide.Options
-	eventDispatcher      event.Dispatcher
-	eventProcessor       event.Processor
-	userProfileService   decision.UserProfileService
-	overrideStore        decision.ExperimentOverrideStore
-	metricsRegistry      metrics.Registry
+	configManager         config.ProjectConfigManager
+	newConfigManager      func(notificationCenter notification.Center) config.ProjectConfigManager
+	notificationCenter    notification.Center
+	ctx                   context.Context
+	decisionService       decision.Service
+	defaultDecideOptions  *decide.Options
+	eventDispatcher       event.Dispatcher
+	eventProcessor        event.Processor
+	eventProcessorOptions []event.BPOptionConfig
+	userProfileService    decision.UserProfileService
+	overrideStore         decision.ExperimentOverrideStore
+	bucketer              bucketer.ExperimentBucketer
+	revisionBucketing     *bool
+	rampClock             func() time.Time
+	banditStore           decision.BanditStore
+	metricsRegistry       metrics.Registry
+	configOverlay         *config.ConfigOverlay
+	datafileRequester     utils.Requester
 }
 
 // OptionFunc is used to provide custom client configuration to the OptimizelyFactory.
@@ -59,7 +69,7 @@ func (f *OptimizelyFactory) Client(clientOptions ...OptionFunc) (*OptimizelyClie
 		opt(f)
 	}
 
-	if f.SDKKey == "" && f.Datafile == nil && f.configManager == nil {
+	if f.SDKKey == "" && f.Datafile == nil && f.configManager == nil && f.newConfigManager == nil {
 		return nil, errors.New("unable to instantiate client: no project config manager, SDK key, or a Datafile provided")
 	}
 
@@ -84,22 +94,36 @@ func (f *OptimizelyFactory) Client(clientOptions ...OptionFunc) (*OptimizelyClie
 		decideOptions = &decide.Options{}
 	}
 
+	// every client has its own notification center unless one is shared with WithNotificationCenter
+	notificationCenter := f.notificationCenter
+	if notificationCenter == nil {
+		notificationCenter = notification.NewNotificationCenter()
+	}
+
 	eg := utils.NewExecGroup(ctx, logging.GetLogger(f.SDKKey, "ExecGroup"))
 	appClient := &OptimizelyClient{
-		defaultDecideOptions: decideOptions,
-		execGroup:            eg,
-		notificationCenter:   registry.GetNotificationCenter(f.SDKKey),
-		logger:               logging.GetLogger(f.SDKKey, "OptimizelyClient"),
+		defaultDecideOptions:   decideOptions,
+		execGroup:              eg,
+		notificationCenter:     notificationCenter,
+		ownsNotificationCenter: f.notificationCenter == nil,
+		logger:                 logging.GetLogger(f.SDKKey, "OptimizelyClient"),
 	}
 
 	if f.configManager != nil {
 		appClient.ConfigManager = f.configManager
+	} else if f.newConfigManager != nil {
+		appClient.ConfigManager = f.newConfigManager(notificationCenter)
 	} else {
-		appClient.ConfigManager = config.NewPollingProjectConfigManager(
-			f.SDKKey,
+		configOptions := []config.OptionFunc{
+			config.WithNotificationCenter(notificationCenter),
 			config.WithInitialDatafile(f.Datafile),
 			config.WithDatafileAccessToken(f.DatafileAccessToken),
-		)
+			config.WithMetricsRegistry(metricsRegistry),
+		}
+		if f.datafileRequester != nil {
+			configOptions = append(configOptions, config.WithRequester(f.datafileRequester))
+		}
+		appClient.ConfigManager = config.NewPollingProjectConfigManager(f.SDKKey, configOptions...)
 	}
 
 	if f.eventProcessor != nil {
@@ -107,11 +131,13 @@ func (f *OptimizelyFactory) Client(clientOptions ...OptionFunc) (*OptimizelyClie
 	} else {
 		var eventProcessorOptions = []event.BPOptionConfig{
 			event.WithSDKKey(f.SDKKey),
+			event.WithNotificationCenter(notificationCenter),
 		}
 		if f.eventDispatcher != nil {
 			eventProcessorOptions = append(eventProcessorOptions, event.WithEventDispatcher(f.eventDispatcher))
 		}
 		eventProcessorOptions = append(eventProcessorOptions, event.WithEventDispatcherMetrics(metricsRegistry))
+		eventProcessorOptions = append(eventProcessorOptions, f.eventProcessorOptions...)
 		appClient.EventProcessor = event.NewBatchEventProcessor(eventProcessorOptions...)
 	}
 
@@ -125,20 +151,68 @@ func (f *OptimizelyFactory) Client(clientOptions ...OptionFunc) (*OptimizelyClie
 		if f.overrideStore != nil {
 			experimentServiceOptions = append(experimentServiceOptions, decision.WithOverrideStore(f.overrideStore))
 		}
+		experimentBucketer := f.bucketer
+		if experimentBucketer == nil && (f.revisionBucketing != nil || f.rampClock != nil) {
+			var bucketerOptions []bucketer.ExperimentBucketerOptionFunc
+			if f.revisionBucketing != nil {
+				bucketerOptions = append(bucketerOptions, bucketer.WithRevisionBucketing(*f.revisionBucketing))
+			}
+			if f.rampClock != nil {
+				bucketerOptions = append(bucketerOptions, bucketer.WithRampClock(f.rampClock))
+			}
+			hashBucketer := bucketer.NewMurmurhashBucketer(logging.GetLogger(f.SDKKey, "ExperimentBucketer"), bucketer.DefaultHashSeed)
+			experimentBucketer = bucketer.NewExperimentBucketer(hashBucketer, bucketerOptions...)
+		}
+		if f.banditStore != nil {
+			experimentServiceOptions = append(experimentServiceOptions, decision.WithBanditStore(f.banditStore))
+		}
+		var featureServiceOptions []decision.CFSOptionFunc
+		if experimentBucketer != nil {
+			experimentServiceOptions = append(experimentServiceOptions, decision.WithBucketer(experimentBucketer))
+			featureServiceOptions = append(featureServiceOptions, decision.WithFeatureBucketer(experimentBucketer))
+		}
 		compositeExperimentService := decision.NewCompositeExperimentService(f.SDKKey, experimentServiceOptions...)
-		compositeService := decision.NewCompositeService(f.SDKKey, decision.WithCompositeExperimentService(compositeExperimentService))
+		compositeFeatureService := decision.NewCompositeFeatureService(f.SDKKey, compositeExperimentService, featureServiceOptions...)
+		compositeService := decision.NewCompositeService(f.SDKKey, decision.WithCompositeExperimentService(compositeExperimentService),
+			decision.WithCompositeFeatureService(compositeFeatureService), decision.WithNotificationCenter(notificationCenter))
 		appClient.DecisionService = compositeService
 	}
 
 	// Initialize the default services with the execution context
-	if pollingConfigManager, ok := appClient.ConfigManager.(*config.PollingProjectConfigManager); ok {
-		eg.Go(pollingConfigManager.Start)
+	switch configManager := appClient.ConfigManager.(type) {
+	case *config.PollingProjectConfigManager:
+		eg.Go(configManager.Start)
+	case *config.FileProjectConfigManager:
+		eg.Go(configManager.Start)
+	case *config.StreamingProjectConfigManager:
+		eg.Go(configManager.Start)
+	}
+	if f.configOverlay != nil {
+		appClient.ConfigManager = config.NewOverlayProjectConfigManager(f.SDKKey, appClient.ConfigManager, f.configOverlay)
 	}
 
 	if batchProcessor, ok := appClient.EventProcessor.(*event.BatchEventProcessor); ok {
 		eg.Go(batchProcessor.Start)
 	}
 
+	if f.banditStore != nil {
+		banditStore := f.banditStore
+		appClient.EventProcessor = &banditEventProcessor{Processor: appClient.EventProcessor, banditStore: banditStore}
+		trackHandler := func(payload interface{}) {
+			trackNotification, ok := payload.(notification.TrackNotification)
+			if !ok {
+				return
+			}
+			if projectConfig, err := appClient.ConfigManager.GetConfig(); err == nil {
+				bucketingID, _ := trackNotification.UserContext.GetBucketingID()
+				decision.TrackBanditConversion(banditStore, projectConfig, trackNotification.EventKey, bucketingID)
+			}
+		}
+		if _, err := notificationCenter.AddHandler(notification.Track, trackHandler); err != nil {
+			appClient.logger.Warning("Unable to track conversions for bandit experiments")
+		}
+	}
+
 	return appClient, nil
 }
 
@@ -152,16 +226,66 @@ func WithDatafileAccessToken(datafileAccessToken string) OptionFunc {
 // WithPollingConfigManager sets polling config manager on a client.
 func WithPollingConfigManager(pollingInterval time.Duration, initDataFile []byte) OptionFunc {
 	return func(f *OptimizelyFactory) {
-		f.configManager = config.NewPollingProjectConfigManager(f.SDKKey, config.WithInitialDatafile(initDataFile),
-			config.WithPollingInterval(pollingInterval))
+		f.setConfigManagerConstructor(func(notificationCenter notification.Center) config.ProjectConfigManager {
+			return config.NewPollingProjectConfigManager(f.SDKKey, config.WithInitialDatafile(initDataFile),
+				config.WithPollingInterval(pollingInterval), config.WithNotificationCenter(notificationCenter))
+		})
 	}
 }
 
 // WithPollingConfigManagerDatafileAccessToken sets polling config manager with auth datafile token on a client
 func WithPollingConfigManagerDatafileAccessToken(pollingInterval time.Duration, initDataFile []byte, datafileAccessToken string) OptionFunc {
 	return func(f *OptimizelyFactory) {
-		f.configManager = config.NewPollingProjectConfigManager(f.SDKKey, config.WithInitialDatafile(initDataFile),
-			config.WithPollingInterval(pollingInterval), config.WithDatafileAccessToken(datafileAccessToken))
+		f.setConfigManagerConstructor(func(notificationCenter notification.Center) config.ProjectConfigManager {
+			return config.NewPollingProjectConfigManager(f.SDKKey, config.WithInitialDatafile(initDataFile),
+				config.WithPollingInterval(pollingInterval), config.WithDatafileAccessToken(datafileAccessToken),
+				config.WithNotificationCenter(notificationCenter))
+		})
+	}
+}
+
+// WithFileConfigManager sets a config manager on a client which loads the datafile from a local path and checks it for changes.
+func WithFileConfigManager(path string, checkInterval time.Duration) OptionFunc {
+	return func(f *OptimizelyFactory) {
+		f.setConfigManagerConstructor(func(notificationCenter notification.Center) config.ProjectConfigManager {
+			return config.NewFileProjectConfigManager(f.SDKKey, path, config.WithFileCheckInterval(checkInterval),
+				config.WithFileNotificationCenter(notificationCenter))
+		})
+	}
+}
+
+// WithStreamingConfigManager sets a config manager on a client which subscribes to a stream of datafile revisions
+// and falls back to polling with the given interval while the stream is down.
+func WithStreamingConfigManager(streamURL string, pollingInterval time.Duration, initDataFile []byte) OptionFunc {
+	return func(f *OptimizelyFactory) {
+		f.setConfigManagerConstructor(func(notificationCenter notification.Center) config.ProjectConfigManager {
+			return config.NewStreamingProjectConfigManager(f.SDKKey, streamURL, config.WithPollingOptions(
+				config.WithInitialDatafile(initDataFile), config.WithPollingInterval(pollingInterval),
+				config.WithDatafileAccessToken(f.DatafileAccessToken), config.WithNotificationCenter(notificationCenter)))
+		})
+	}
+}
+
+// WithNotificationCenter sets the notification center of a client, which is otherwise created for each client. A shared
+// center is not cleared when the client is closed. Config managers set with WithConfigManager keep their own center.
+func WithNotificationCenter(notificationCenter notification.Center) OptionFunc {
+	return func(f *OptimizelyFactory) {
+		f.notificationCenter = notificationCenter
+	}
+}
+
+// WithConfigOverlay sets a local override document which is applied on top of every config of the config manager.
+func WithConfigOverlay(overlay *config.ConfigOverlay) OptionFunc {
+	return func(f *OptimizelyFactory) {
+		f.configOverlay = overlay
+	}
+}
+
+// WithDatafileRequester sets the requester the default config manager fetches the datafile with, which allows several
+// clients to share the same HTTP client. It is replaced by an authenticated requester when a datafile access token is set.
+func WithDatafileRequester(requester utils.Requester) OptionFunc {
+	return func(f *OptimizelyFactory) {
+		f.datafileRequester = requester
 	}
 }
 
@@ -169,9 +293,16 @@ func WithPollingConfigManagerDatafileAccessToken(pollingInterval time.Duration,
 func WithConfigManager(configManager config.ProjectConfigManager) OptionFunc {
 	return func(f *OptimizelyFactory) {
 		f.configManager = configManager
+		f.newConfigManager = nil
 	}
 }
 
+// setConfigManagerConstructor defers the creation of the config manager until the notification center of the client is known
+func (f *OptimizelyFactory) setConfigManagerConstructor(newConfigManager func(notificationCenter notification.Center) config.ProjectConfigManager) {
+	f.configManager = nil
+	f.newConfigManager = newConfigManager
+}
+
 // WithDecisionService sets decision service on a client.
 func WithDecisionService(decisionService decision.Service) OptionFunc {
 	return func(f *OptimizelyFactory) {
@@ -200,11 +331,47 @@ func WithExperimentOverrides(overrideStore decision.ExperimentOverrideStore) Opt
 	}
 }
 
-// WithBatchEventProcessor sets event processor on a client.
+// WithBucketer sets the experiment bucketer used by the decision service for both experiments and rollouts.
+func WithBucketer(experimentBucketer bucketer.ExperimentBucketer) OptionFunc {
+	return func(f *OptimizelyFactory) {
+		f.bucketer = experimentBucketer
+	}
+}
+
+// WithRevisionBucketing sets whether the default bucketer re-randomizes users when an experiment's revision changes.
+// Experiments setting bucketByRevision in the datafile override it, and it has no effect together with WithBucketer.
+func WithRevisionBucketing(enabled bool) OptionFunc {
+	return func(f *OptimizelyFactory) {
+		f.revisionBucketing = &enabled
+	}
+}
+
+// WithRampClock sets the clock the default bucketer evaluates ramp schedules against, it has no effect together with
+// WithBucketer.
+func WithRampClock(now func() time.Time) OptionFunc {
+	return func(f *OptimizelyFactory) {
+		f.rampClock = now
+	}
+}
+
+// WithBandits enables bandit allocation for experiments carrying bandit metadata in the datafile. Exposures are
+// observed from the impression events and conversions from the Track calls of the client, and kept in the given
+// store. An unbounded in-memory decision.MapBanditStore is used when it is nil.
+func WithBandits(banditStore decision.BanditStore) OptionFunc {
+	return func(f *OptimizelyFactory) {
+		if banditStore == nil {
+			banditStore = decision.NewMapBanditStore()
+		}
+		f.banditStore = banditStore
+	}
+}
+
+// WithBatchEventProcessor sets the batch size, queue size and flush interval of the event processor of a client.
 func WithBatchEventProcessor(batchSize, queueSize int, flushInterval time.Duration) OptionFunc {
 	return func(f *OptimizelyFactory) {
-		f.eventProcessor = event.NewBatchEventProcessor(event.WithBatchSize(batchSize),
-			event.WithQueueSize(queueSize), event.WithFlushInterval(flushInterval))
+		f.eventProcessor = nil
+		f.eventProcessorOptions = []event.BPOptionConfig{event.WithBatchSize(batchSize),
+			event.WithQueueSize(queueSize), event.WithFlushInterval(flushInterval)}
 	}
 }
 
@@ -19,16 +19,23 @@ package client
 import (
 	"context"
 	"errors"
+	"io/ioutil"
 	"net/http"
+	"os"
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/WolffunService/experiment/pkg/config"
+	"github.com/WolffunService/experiment/pkg/config/datafilebuilder"
 	"github.com/WolffunService/experiment/pkg/decide"
 	"github.com/WolffunService/experiment/pkg/decision"
+	"github.com/WolffunService/experiment/pkg/decision/reasons"
+	"github.com/WolffunService/experiment/pkg/entities"
 	"github.com/WolffunService/experiment/pkg/event"
 	"github.com/WolffunService/experiment/pkg/metrics"
+	"github.com/WolffunService/experiment/pkg/notification"
 	"github.com/WolffunService/experiment/pkg/utils"
 
 	"github.com/stretchr/testify/assert"
@@ -93,6 +100,207 @@ func TestClientWithPollingConfigManagerDatafileAccessToken(t *testing.T) {
 	assert.NotNil(t, optimizelyClient.DecisionService)
 	assert.NotNil(t, optimizelyClient.EventProcessor)
 }
+func TestClientWithFileConfigManager(t *testing.T) {
+	dir, err := ioutil.TempDir("", "factory_test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "datafile.json")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(`{"revision":"42","version":"4"}`), 0600))
+	factory := OptimizelyFactory{}
+
+	optimizelyClient, err := factory.Client(WithFileConfigManager(path, time.Hour))
+	assert.NoError(t, err)
+	assert.IsType(t, &config.FileProjectConfigManager{}, optimizelyClient.ConfigManager)
+	projectConfig, err := optimizelyClient.ConfigManager.GetConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "42", projectConfig.GetRevision())
+	optimizelyClient.Close()
+}
+
+func TestClientWithConfigOverlay(t *testing.T) {
+	factory := OptimizelyFactory{}
+	mockDatafile := []byte(`{"version":"4","revision":"42","experiments":[{"id":"e1","key":"exp_1","status":"Running"}]}`)
+	configManager := config.NewStaticProjectConfigManagerWithOptions("", config.WithInitialDatafile(mockDatafile))
+	overlay := &config.ConfigOverlay{Experiments: map[string]config.ExperimentOverride{"exp_1": {Disabled: true}}}
+
+	optimizelyClient, err := factory.Client(WithConfigManager(configManager), WithConfigOverlay(overlay))
+	assert.NoError(t, err)
+	assert.IsType(t, &config.OverlayProjectConfigManager{}, optimizelyClient.ConfigManager)
+	projectConfig, err := optimizelyClient.ConfigManager.GetConfig()
+	assert.NoError(t, err)
+	experiment, err := projectConfig.GetExperimentByKey("exp_1")
+	assert.NoError(t, err)
+	assert.False(t, experiment.IsRunning())
+}
+
+type recordingExperimentBucketer struct {
+	experimentKeys []string
+}
+
+func (r *recordingExperimentBucketer) Bucket(bucketingID string, experiment entities.Experiment, group entities.Group) (*entities.Variation, reasons.Reason, error) {
+	r.experimentKeys = append(r.experimentKeys, experiment.Key)
+	return nil, reasons.NotBucketedIntoVariation, nil
+}
+
+func TestClientWithBucketer(t *testing.T) {
+	datafile := datafilebuilder.New().
+		Holdout("global_holdout", 100).
+		Flag("flag_1").
+		Experiment("exp_1").Variation("a", true).Variation("b", true).
+		Rule("rule_1").
+		MustBuild()
+	experimentBucketer := &recordingExperimentBucketer{}
+
+	optimizelyClient, err := (&OptimizelyFactory{Datafile: datafile}).Client(WithBucketer(experimentBucketer))
+	assert.NoError(t, err)
+	defer optimizelyClient.Close()
+
+	userContext := optimizelyClient.CreateUserContext("user_1", nil)
+	decision := userContext.Decide("flag_1", nil)
+	assert.False(t, decision.Enabled)
+	// the bucketer is used for the holdout, the experiment and the rollout rule of the flag
+	assert.Equal(t, []string{"global_holdout", "exp_1", "rule_1"}, experimentBucketer.experimentKeys)
+}
+
+func TestClientWithRampClock(t *testing.T) {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	datafile := datafilebuilder.New().
+		Flag("flag_1").Rule("rule_1").Ramp(start, 0).Ramp(start.Add(24*time.Hour), 100).
+		MustBuild()
+	now := start
+
+	optimizelyClient, err := (&OptimizelyFactory{Datafile: datafile}).Client(WithRampClock(func() time.Time { return now }))
+	assert.NoError(t, err)
+	defer optimizelyClient.Close()
+
+	userContext := optimizelyClient.CreateUserContext("user_1", nil)
+	assert.False(t, userContext.Decide("flag_1", nil).Enabled)
+	// the rule is rolled out to everyone at the next step, without a new datafile
+	now = start.Add(24 * time.Hour)
+	assert.True(t, userContext.Decide("flag_1", nil).Enabled)
+	assert.Equal(t, 100.0, optimizelyClient.GetOptimizelyConfig().FeaturesMap["flag_1"].DeliveryRules[0].TrafficPercentage(now))
+}
+
+func TestClientWithBandits(t *testing.T) {
+	builder := datafilebuilder.New()
+	builder.Flag("flag_1").
+		Experiment("exp_1").Bandit(entities.BanditEpsilonGreedy, 0, "").Variation("a", true).Variation("b", true)
+	builder.Event("purchase", "exp_1")
+	eventProcessor := new(MockProcessor)
+	eventProcessor.On("ProcessEvent", mock.Anything).Return(true)
+	banditStore := decision.NewMapBanditStore()
+
+	optimizelyClient, err := (&OptimizelyFactory{Datafile: builder.MustBuild()}).Client(WithBandits(banditStore), WithEventProcessor(eventProcessor))
+	assert.NoError(t, err)
+	defer optimizelyClient.Close()
+
+	experimentID := builder.ExperimentID("exp_1")
+	variationID := builder.VariationID("exp_1", "a")
+	userContext := optimizelyClient.CreateUserContext("user_1", map[string]interface{}{"$opt_bucketing_id": "household_1"})
+
+	// decisions without an impression event are not exposures, so their conversions are not counted either
+	firstDecision := userContext.Decide("flag_1", []decide.OptimizelyDecideOptions{decide.DisableDecisionEvent})
+	assert.Equal(t, "a", firstDecision.VariationKey)
+	assert.NoError(t, userContext.TrackEvent("purchase", nil))
+	assert.Equal(t, decision.BanditArm{}, banditStore.GetArms(experimentID)[variationID])
+
+	// users are identified by their bucketing ID
+	assert.Equal(t, "a", userContext.Decide("flag_1", nil).VariationKey)
+	assert.NoError(t, userContext.TrackEvent("purchase", nil))
+	assert.Equal(t, decision.BanditArm{Exposures: 1, Conversions: 1}, banditStore.GetArms(experimentID)[variationID])
+	assignedVariationID, ok := banditStore.GetAssignment(experimentID, "household_1")
+	assert.True(t, ok)
+	assert.Equal(t, variationID, assignedVariationID)
+}
+
+func TestClientWithGlobalHoldout(t *testing.T) {
+	builder := datafilebuilder.New().Holdout("global_holdout", 100)
+	builder.Flag("flag_1").Variable("title", entities.String, "default").
+		Rule("everyone").Variation("on", true).VariableValue("title", "launched")
+	eventProcessor := new(MockProcessor)
+	eventProcessor.On("ProcessEvent", mock.Anything).Return(true)
+
+	optimizelyClient, err := (&OptimizelyFactory{Datafile: builder.MustBuild()}).Client(WithEventProcessor(eventProcessor))
+	assert.NoError(t, err)
+	defer optimizelyClient.Close()
+
+	userContext := optimizelyClient.CreateUserContext("user_1", nil)
+	flagDecision := userContext.Decide("flag_1", nil)
+	assert.False(t, flagDecision.Enabled)
+	assert.Equal(t, "global_holdout", flagDecision.RuleKey)
+	assert.Equal(t, "off", flagDecision.VariationKey)
+	assert.Equal(t, map[string]interface{}{"title": "default"}, flagDecision.Variables.ToMap())
+
+	// held out users still get an impression, so the holdout can be measured
+	if assert.Len(t, eventProcessor.Events, 1) {
+		impression := eventProcessor.Events[0].Impression
+		assert.Equal(t, builder.HoldoutID("global_holdout"), impression.ExperimentID)
+		assert.Equal(t, decision.Holdout, impression.Metadata.RuleType)
+	}
+}
+
+func TestClientsHaveTheirOwnNotificationCenter(t *testing.T) {
+	datafile := []byte(`{"version":"4","revision":"42"}`)
+	clientA, err := (&OptimizelyFactory{Datafile: datafile}).Client()
+	assert.NoError(t, err)
+	clientB, err := (&OptimizelyFactory{Datafile: datafile}).Client(WithBatchEventProcessor(10, 100, time.Hour))
+	assert.NoError(t, err)
+	defer clientB.Close()
+	assert.True(t, clientA.GetNotificationCenter() != clientB.GetNotificationCenter())
+
+	// the config manager, decision service and event processor of a client all use its notification center
+	calls := map[notification.Type]int{}
+	_, err = clientA.ConfigManager.OnProjectConfigUpdate(func(notification.ProjectConfigUpdateNotification) { calls[notification.ProjectConfigUpdate]++ })
+	assert.NoError(t, err)
+	_, err = clientA.DecisionService.OnDecision(func(notification.DecisionNotification) { calls[notification.Decision]++ })
+	assert.NoError(t, err)
+	_, err = clientA.EventProcessor.OnEventDispatch(func(event.LogEvent) { calls[notification.LogEvent]++ })
+	assert.NoError(t, err)
+
+	payloads := map[notification.Type]interface{}{
+		notification.ProjectConfigUpdate: notification.ProjectConfigUpdateNotification{},
+		notification.Decision:            notification.DecisionNotification{},
+		notification.LogEvent:            event.LogEvent{},
+	}
+	for notificationType, payload := range payloads {
+		assert.NoError(t, clientB.GetNotificationCenter().Send(notificationType, payload))
+	}
+	assert.Empty(t, calls)
+	for notificationType, payload := range payloads {
+		assert.NoError(t, clientA.GetNotificationCenter().Send(notificationType, payload))
+	}
+	assert.Equal(t, map[notification.Type]int{notification.ProjectConfigUpdate: 1, notification.Decision: 1, notification.LogEvent: 1}, calls)
+
+	// the handlers are released when the client is closed
+	clientA.Close()
+	for notificationType, payload := range payloads {
+		assert.NoError(t, clientA.GetNotificationCenter().Send(notificationType, payload))
+	}
+	assert.Equal(t, map[notification.Type]int{notification.ProjectConfigUpdate: 1, notification.Decision: 1, notification.LogEvent: 1}, calls)
+}
+
+func TestClientWithNotificationCenter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "factory_test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "datafile.json")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(`{"revision":"42","version":"4"}`), 0600))
+
+	notificationCenter := notification.NewNotificationCenter()
+	optimizelyClient, err := (&OptimizelyFactory{}).Client(WithNotificationCenter(notificationCenter), WithFileConfigManager(path, time.Hour))
+	assert.NoError(t, err)
+	assert.True(t, optimizelyClient.GetNotificationCenter() == notificationCenter)
+
+	calls := 0
+	_, err = optimizelyClient.ConfigManager.OnProjectConfigUpdate(func(notification.ProjectConfigUpdateNotification) { calls++ })
+	assert.NoError(t, err)
+
+	// a shared notification center is not cleared when the client is closed
+	optimizelyClient.Close()
+	notificationCenter.Send(notification.ProjectConfigUpdate, notification.ProjectConfigUpdateNotification{})
+	assert.Equal(t, 1, calls)
+}
+
 func TestClientWithProjectConfigManagerInOptions(t *testing.T) {
 	factory := OptimizelyFactory{}
 	mockDatafile := []byte(`{"version":"4"}`)
@@ -20,6 +20,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/WolffunService/experiment/pkg/entities"
 	"github.com/WolffunService/experiment/pkg/optimizelyjson"
 
 	"github.com/stretchr/testify/suite"
@@ -46,7 +47,7 @@ func (s *OptimizelyDecisionTestSuite) TestOptimizelyDecision() {
 	attributes := map[string]interface{}{"key": 1212}
 
 	optimizelyUserContext := s.OptimizelyClient.CreateUserContext(userID, attributes)
-	decision := NewOptimizelyDecision(variationKey, ruleKey, flagKey, enabled, variables, optimizelyUserContext, reasons)
+	decision := NewOptimizelyDecision(variationKey, ruleKey, flagKey, enabled, variables, optimizelyUserContext, reasons, entities.Experiment{})
 
 	s.Equal(variationKey, decision.VariationKey)
 	s.Equal(enabled, decision.Enabled)
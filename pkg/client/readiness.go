@@ -0,0 +1,96 @@
+/****************************************************************************
+ * Copyright 2026, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package client has client definitions
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/WolffunService/experiment/pkg/config"
+)
+
+// readinessCheckInterval is how often WaitForReady checks the config manager for errors
+var readinessCheckInterval = 100 * time.Millisecond
+
+// NotReadyError is returned by WaitForReady when the client has no valid config before the context is done
+type NotReadyError struct {
+	Err             error // the error of the context
+	LastConfigError error // the last error of the config manager such as config.ErrDatafileParse, nil if no fetch completed yet
+}
+
+func (e *NotReadyError) Error() string {
+	if e.LastConfigError == nil {
+		return fmt.Sprintf("optimizely client is not ready: %v", e.Err)
+	}
+	return fmt.Sprintf("optimizely client is not ready: %v, last config error: %v", e.Err, e.LastConfigError)
+}
+
+// WaitForReady blocks until the client has a valid config. It returns config.Err403Forbidden as soon as the datafile
+// request is forbidden, and a *NotReadyError carrying the last config error when the context is done first.
+func (o *OptimizelyClient) WaitForReady(ctx context.Context) error {
+	if isNil(o.ConfigManager) {
+		return errors.New("project config manager is not initialized")
+	}
+
+	// a nil channel never fires, managers which are not ReadyNotifiers are only checked periodically
+	var ready <-chan struct{}
+	if readyNotifier, ok := o.ConfigManager.(config.ReadyNotifier); ok {
+		ready = readyNotifier.Ready()
+	}
+	ticker := time.NewTicker(readinessCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		projectConfig, err := o.ConfigManager.GetConfig()
+		if err == nil && projectConfig != nil {
+			return nil
+		}
+		if err == config.Err403Forbidden {
+			return err
+		}
+
+		select {
+		case <-ready:
+		case <-ticker.C:
+		case <-ctx.Done():
+			if projectConfig, err = o.ConfigManager.GetConfig(); err == nil && projectConfig != nil {
+				return nil
+			}
+			return &NotReadyError{Err: ctx.Err(), LastConfigError: err}
+		}
+	}
+}
+
+// OnReady calls the callback once the client has a valid config. The callback is not called when the client is closed
+// first or the datafile request is forbidden.
+func (o *OptimizelyClient) OnReady(callback func()) {
+	onReady := func(ctx context.Context) {
+		if err := o.WaitForReady(ctx); err != nil {
+			o.logger.Warning(fmt.Sprintf("OnReady callback not called: %v", err))
+			return
+		}
+		callback()
+	}
+	if o.execGroup == nil {
+		go onReady(context.Background())
+		return
+	}
+	o.execGroup.Go(onReady)
+}
@@ -0,0 +1,148 @@
+/****************************************************************************
+ * Copyright 2026, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/WolffunService/experiment/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type readinessConfigManager struct {
+	config.ProjectConfigManager
+	lock          sync.Mutex
+	projectConfig config.ProjectConfig
+	err           error
+}
+
+func (m *readinessConfigManager) GetConfig() (config.ProjectConfig, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.projectConfig, m.err
+}
+
+func (m *readinessConfigManager) set(projectConfig config.ProjectConfig, err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.projectConfig, m.err = projectConfig, err
+}
+
+type readyNotifierConfigManager struct {
+	readinessConfigManager
+	ready chan struct{}
+}
+
+func (m *readyNotifierConfigManager) Ready() <-chan struct{} {
+	return m.ready
+}
+
+func TestWaitForReadyReturnsWhenConfigIsSet(t *testing.T) {
+	configManager := &readyNotifierConfigManager{ready: make(chan struct{})}
+	client := OptimizelyClient{ConfigManager: configManager}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		configManager.set(&MockProjectConfig{}, nil)
+		close(configManager.ready)
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, client.WaitForReady(ctx))
+}
+
+func TestWaitForReadyChecksManagersWithoutReadyNotifier(t *testing.T) {
+	configManager := &readinessConfigManager{}
+	client := OptimizelyClient{ConfigManager: configManager}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		configManager.set(&MockProjectConfig{}, nil)
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, client.WaitForReady(ctx))
+}
+
+func TestWaitForReadyFailsFastOnForbidden(t *testing.T) {
+	configManager := &readyNotifierConfigManager{ready: make(chan struct{})}
+	client := OptimizelyClient{ConfigManager: configManager}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		configManager.set(nil, config.Err403Forbidden)
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	start := time.Now()
+	assert.Equal(t, config.Err403Forbidden, client.WaitForReady(ctx))
+	assert.True(t, time.Since(start) < time.Second)
+}
+
+func TestWaitForReadyTimeout(t *testing.T) {
+	configManager := &readyNotifierConfigManager{ready: make(chan struct{})}
+	client := OptimizelyClient{ConfigManager: configManager}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := client.WaitForReady(ctx)
+	if assert.IsType(t, &NotReadyError{}, err) {
+		assert.Equal(t, context.DeadlineExceeded, err.(*NotReadyError).Err)
+		assert.Nil(t, err.(*NotReadyError).LastConfigError)
+	}
+
+	configManager.set(nil, config.ErrDatafileParse)
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err = client.WaitForReady(ctx)
+	if assert.IsType(t, &NotReadyError{}, err) {
+		assert.Equal(t, config.ErrDatafileParse, err.(*NotReadyError).LastConfigError)
+		assert.Contains(t, err.Error(), "unable to parse datafile")
+	}
+
+	assert.Error(t, (&OptimizelyClient{}).WaitForReady(context.Background()))
+}
+
+func TestOnReady(t *testing.T) {
+	factory := OptimizelyFactory{SDKKey: "on_ready"}
+	configManager := &readyNotifierConfigManager{ready: make(chan struct{})}
+	client, err := factory.Client(WithConfigManager(configManager))
+	assert.NoError(t, err)
+
+	called := make(chan struct{})
+	client.OnReady(func() { close(called) })
+
+	configManager.set(&MockProjectConfig{}, nil)
+	close(configManager.ready)
+	select {
+	case <-called:
+	case <-time.After(time.Second):
+		t.Error(errors.New("OnReady callback was not called"))
+	}
+	client.Close()
+}
+
+func TestWaitForReadyWithPollingConfigManager(t *testing.T) {
+	configManager := config.NewAsyncPollingProjectConfigManager("wait_for_ready", config.WithInitialDatafile([]byte(`{"revision":"42","version": "4"}`)))
+	client := OptimizelyClient{ConfigManager: configManager}
+	assert.NoError(t, client.WaitForReady(context.Background()))
+}
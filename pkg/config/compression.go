@@ -0,0 +1,70 @@
+/****************************************************************************
+ * Copyright 2026, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package config //
+package config
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/WolffunService/experiment/pkg/metrics"
+	"github.com/WolffunService/experiment/pkg/utils"
+)
+
+// MaxDecompressedDatafileSize limits the size a compressed datafile may decompress to
+const MaxDecompressedDatafileSize = 100 << 20
+
+// AcceptEncoding header key for request
+const AcceptEncoding = "Accept-Encoding"
+
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// datafileRequestHeaders are the default headers of the datafile requests
+func datafileRequestHeaders() []utils.Header {
+	return []utils.Header{{Name: "Content-Type", Value: "application/json"}, {Name: "Accept", Value: "application/json"},
+		{Name: AcceptEncoding, Value: "gzip"}}
+}
+
+// decompressDatafile returns the datafile as is unless it is gzip compressed, the sizes of compressed datafiles are
+// reported to the metrics registry
+func decompressDatafile(datafile []byte, metricsRegistry metrics.Registry) ([]byte, error) {
+	if !bytes.HasPrefix(datafile, gzipMagic) {
+		return datafile, nil
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(datafile))
+	if err != nil {
+		return nil, fmt.Errorf("unable to decompress datafile: %v", err)
+	}
+	defer reader.Close()
+
+	decompressed, err := ioutil.ReadAll(io.LimitReader(reader, MaxDecompressedDatafileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("unable to decompress datafile: %v", err)
+	}
+	if len(decompressed) > MaxDecompressedDatafileSize {
+		return nil, fmt.Errorf("unable to decompress datafile: larger than %d bytes", MaxDecompressedDatafileSize)
+	}
+
+	if metricsRegistry != nil {
+		metricsRegistry.GetCounter(metrics.DatafileCompressedBytes).Add(float64(len(datafile)))
+		metricsRegistry.GetCounter(metrics.DatafileDecompressedBytes).Add(float64(len(decompressed)))
+	}
+	return decompressed, nil
+}
@@ -0,0 +1,154 @@
+/****************************************************************************
+ * Copyright 2026, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package config //
+package config
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/WolffunService/experiment/pkg/logging"
+	"github.com/WolffunService/experiment/pkg/metrics"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testMetricsRegistry struct {
+	lock     sync.Mutex
+	counters map[string]*testCounter
+}
+
+type testCounter struct {
+	value float64
+}
+
+func (c *testCounter) Add(delta float64) {
+	c.value += delta
+}
+
+func newTestMetricsRegistry() *testMetricsRegistry {
+	return &testMetricsRegistry{counters: map[string]*testCounter{}}
+}
+
+func (r *testMetricsRegistry) GetCounter(name string) metrics.Counter {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if _, ok := r.counters[name]; !ok {
+		r.counters[name] = &testCounter{}
+	}
+	return r.counters[name]
+}
+
+func (r *testMetricsRegistry) GetGauge(name string) metrics.Gauge {
+	return &metrics.NoopGauge{}
+}
+
+func (r *testMetricsRegistry) value(name string) float64 {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if counter, ok := r.counters[name]; ok {
+		return counter.value
+	}
+	return 0
+}
+
+func gzipDatafile(t *testing.T, datafile string) []byte {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	_, err := writer.Write([]byte(datafile))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+	return buf.Bytes()
+}
+
+func TestDecompressDatafile(t *testing.T) {
+	datafile := `{"revision":"42","version": "4"}`
+	metricsRegistry := newTestMetricsRegistry()
+
+	decompressed, err := decompressDatafile([]byte(datafile), metricsRegistry)
+	assert.NoError(t, err)
+	assert.Equal(t, datafile, string(decompressed))
+	assert.Equal(t, float64(0), metricsRegistry.value(metrics.DatafileDecompressedBytes))
+
+	compressed := gzipDatafile(t, datafile)
+	decompressed, err = decompressDatafile(compressed, metricsRegistry)
+	assert.NoError(t, err)
+	assert.Equal(t, datafile, string(decompressed))
+	assert.Equal(t, float64(len(compressed)), metricsRegistry.value(metrics.DatafileCompressedBytes))
+	assert.Equal(t, float64(len(datafile)), metricsRegistry.value(metrics.DatafileDecompressedBytes))
+
+	_, err = decompressDatafile(compressed[:len(compressed)/2], metricsRegistry)
+	assert.Error(t, err)
+}
+
+func TestSyncConfigDecompressesGzipResponse(t *testing.T) {
+	datafile := `{"revision":"42","version": "4"}`
+	compressed := gzipDatafile(t, datafile)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(AcceptEncoding) != "gzip" {
+			fmt.Fprint(w, datafile)
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(compressed)
+	}))
+	defer server.Close()
+
+	metricsRegistry := newTestMetricsRegistry()
+	configManager := NewPollingProjectConfigManager("gzip_datafile", WithDatafileURLTemplate(server.URL+"/%s.json"),
+		WithMetricsRegistry(metricsRegistry))
+	assert.Equal(t, "42", currentRevision(configManager))
+	assert.Equal(t, float64(len(compressed)), metricsRegistry.value(metrics.DatafileCompressedBytes))
+	assert.Equal(t, float64(len(datafile)), metricsRegistry.value(metrics.DatafileDecompressedBytes))
+}
+
+func TestFileManagerLoadsGzipDatafile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "compression_test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "datafile.json.gz")
+	assert.NoError(t, ioutil.WriteFile(path, gzipDatafile(t, `{"revision":"42","version": "4"}`), 0600))
+
+	metricsRegistry := newTestMetricsRegistry()
+	configManager := NewFileProjectConfigManager("gzip_file", path, WithFileCheckInterval(time.Hour), WithFileMetricsRegistry(metricsRegistry))
+	assert.Equal(t, "42", currentRevision(configManager))
+	assert.NotZero(t, metricsRegistry.value(metrics.DatafileDecompressedBytes))
+}
+
+func TestStaticManagerLoadsGzipDatafile(t *testing.T) {
+	configManager := NewStaticProjectConfigManagerWithOptions("", WithInitialDatafile(gzipDatafile(t, `{"revision":"42","version": "4"}`)))
+	if assert.NotNil(t, configManager) {
+		assert.Equal(t, "42", currentRevision(configManager))
+	}
+}
+
+func TestStaticManagerFromPayloadLoadsGzipDatafile(t *testing.T) {
+	configManager, err := NewStaticProjectConfigManagerFromPayload(gzipDatafile(t, `{"revision":"42","version": "4"}`), logging.GetLogger("", "StaticProjectConfigManager"))
+	assert.NoError(t, err)
+	if assert.NotNil(t, configManager) {
+		assert.Equal(t, "42", currentRevision(configManager))
+	}
+}
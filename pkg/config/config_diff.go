@@ -0,0 +1,125 @@
+/****************************************************************************
+ * Copyright 2026, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package config //
+package config
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/WolffunService/experiment/pkg/entities"
+	"github.com/WolffunService/experiment/pkg/notification"
+)
+
+// newProjectConfigUpdateNotification builds the notification sent when the new config replaces the old one
+func newProjectConfigUpdateNotification(oldConfig, newConfig ProjectConfig) notification.ProjectConfigUpdateNotification {
+	projectConfigUpdateNotification := notification.ProjectConfigUpdateNotification{
+		Type:     notification.ProjectConfigUpdate,
+		Revision: newConfig.GetRevision(),
+		Diff:     DiffProjectConfigs(oldConfig, newConfig),
+	}
+	if oldConfig != nil {
+		projectConfigUpdateNotification.OldRevision = oldConfig.GetRevision()
+	}
+	return projectConfigUpdateNotification
+}
+
+// DiffProjectConfigs computes what changed from the old to the new project config, a nil old config yields everything as added.
+// Experiments and rules are identified by ID, since rule keys are only unique within a flag.
+func DiffProjectConfigs(oldConfig, newConfig ProjectConfig) notification.ConfigDiff {
+	oldFlags, newFlags := map[string]interface{}{}, map[string]interface{}{}
+	oldExperiments, newExperiments := map[string]interface{}{}, map[string]interface{}{}
+	oldRollouts, newRollouts := map[string]interface{}{}, map[string]interface{}{}
+	oldAudiences, newAudiences := map[string]interface{}{}, map[string]interface{}{}
+	oldRules, newRules := map[string]ruleAllocation{}, map[string]ruleAllocation{}
+
+	collect := func(projectConfig ProjectConfig, flags, experiments, rollouts, audiences map[string]interface{}, rules map[string]ruleAllocation) {
+		if projectConfig == nil {
+			return
+		}
+		for _, feature := range projectConfig.GetFeatureList() {
+			flags[feature.Key] = feature
+		}
+		for _, experiment := range projectConfig.GetExperimentList() {
+			experiments[experiment.ID] = experiment
+			rules[experiment.ID] = ruleAllocation{experiment: experiment}
+		}
+		for _, rollout := range projectConfig.GetRolloutList() {
+			rollouts[rollout.ID] = rollout
+			for _, rule := range rollout.Experiments {
+				rules[rule.ID] = ruleAllocation{experiment: rule, rolloutID: rollout.ID}
+			}
+		}
+		for _, audience := range projectConfig.GetAudienceList() {
+			audiences[audience.ID] = audience
+		}
+	}
+	collect(oldConfig, oldFlags, oldExperiments, oldRollouts, oldAudiences, oldRules)
+	collect(newConfig, newFlags, newExperiments, newRollouts, newAudiences, newRules)
+
+	return notification.ConfigDiff{
+		Flags:              diffEntities(oldFlags, newFlags),
+		Experiments:        diffEntities(oldExperiments, newExperiments),
+		Rollouts:           diffEntities(oldRollouts, newRollouts),
+		Audiences:          diffEntities(oldAudiences, newAudiences),
+		TrafficAllocations: diffTrafficAllocations(oldRules, newRules),
+	}
+}
+
+type ruleAllocation struct {
+	experiment entities.Experiment
+	rolloutID  string
+}
+
+func diffEntities(oldEntities, newEntities map[string]interface{}) (changes notification.EntityChanges) {
+	for id, newEntity := range newEntities {
+		oldEntity, ok := oldEntities[id]
+		switch {
+		case !ok:
+			changes.Added = append(changes.Added, id)
+		case !reflect.DeepEqual(oldEntity, newEntity):
+			changes.Changed = append(changes.Changed, id)
+		}
+	}
+	for id := range oldEntities {
+		if _, ok := newEntities[id]; !ok {
+			changes.Removed = append(changes.Removed, id)
+		}
+	}
+	sort.Strings(changes.Added)
+	sort.Strings(changes.Removed)
+	sort.Strings(changes.Changed)
+	return changes
+}
+
+func diffTrafficAllocations(oldRules, newRules map[string]ruleAllocation) (changes []notification.TrafficAllocationChange) {
+	for id, newRule := range newRules {
+		oldRule, ok := oldRules[id]
+		if !ok || reflect.DeepEqual(oldRule.experiment.TrafficAllocation, newRule.experiment.TrafficAllocation) {
+			continue
+		}
+		changes = append(changes, notification.TrafficAllocationChange{
+			RuleID:    id,
+			RuleKey:   newRule.experiment.Key,
+			RolloutID: newRule.rolloutID,
+			Old:       oldRule.experiment.TrafficAllocation,
+			New:       newRule.experiment.TrafficAllocation,
+		})
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].RuleID < changes[j].RuleID })
+	return changes
+}
@@ -0,0 +1,163 @@
+/****************************************************************************
+ * Copyright 2026, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package config //
+package config
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/WolffunService/experiment/pkg/config/datafileprojectconfig"
+	"github.com/WolffunService/experiment/pkg/entities"
+	"github.com/WolffunService/experiment/pkg/logging"
+	"github.com/WolffunService/experiment/pkg/notification"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const diffOldDatafile = `{
+	"revision": "1", "version": "4",
+	"audiences": [{"id": "a1", "name": "one", "conditions": "[\"or\", {\"type\": \"custom_attribute\", \"name\": \"x\", \"value\": 1}]"},
+		{"id": "a2", "name": "two", "conditions": "[\"or\", {\"type\": \"custom_attribute\", \"name\": \"y\", \"value\": 1}]"}],
+	"experiments": [
+		{"id": "e1", "key": "exp_1", "layerId": "l1", "status": "Running", "audienceIds": [],
+			"variations": [{"id": "v1", "key": "a"}, {"id": "v2", "key": "b"}],
+			"trafficAllocation": [{"entityId": "v1", "endOfRange": 5000}, {"entityId": "v2", "endOfRange": 10000}]},
+		{"id": "e2", "key": "exp_2", "layerId": "l2", "status": "Running", "audienceIds": [],
+			"variations": [{"id": "v3", "key": "a"}],
+			"trafficAllocation": [{"entityId": "v3", "endOfRange": 10000}]}
+	],
+	"rollouts": [{"id": "r1", "experiments": [{"id": "rule1", "key": "rule_1", "layerId": "r1", "status": "Running", "audienceIds": [],
+		"variations": [{"id": "v4", "key": "on", "featureEnabled": true}],
+		"trafficAllocation": [{"entityId": "v4", "endOfRange": 1000}]}]}],
+	"featureFlags": [{"id": "f1", "key": "flag_1", "rolloutId": "r1", "experimentIds": ["e1"], "variables": []}]
+}`
+
+const diffNewDatafile = `{
+	"revision": "2", "version": "4",
+	"audiences": [{"id": "a1", "name": "one", "conditions": "[\"or\", {\"type\": \"custom_attribute\", \"name\": \"x\", \"value\": 2}]"},
+		{"id": "a3", "name": "three", "conditions": "[\"or\", {\"type\": \"custom_attribute\", \"name\": \"z\", \"value\": 1}]"}],
+	"experiments": [
+		{"id": "e1", "key": "exp_1", "layerId": "l1", "status": "Running", "audienceIds": [],
+			"variations": [{"id": "v1", "key": "a"}, {"id": "v2", "key": "b"}],
+			"trafficAllocation": [{"entityId": "v1", "endOfRange": 2000}, {"entityId": "v2", "endOfRange": 4000}]},
+		{"id": "e3", "key": "exp_3", "layerId": "l3", "status": "Running", "audienceIds": [],
+			"variations": [{"id": "v5", "key": "a"}],
+			"trafficAllocation": [{"entityId": "v5", "endOfRange": 10000}]}
+	],
+	"rollouts": [{"id": "r1", "experiments": [{"id": "rule1", "key": "rule_1", "layerId": "r1", "status": "Running", "audienceIds": [],
+		"variations": [{"id": "v4", "key": "on", "featureEnabled": true}],
+		"trafficAllocation": [{"entityId": "v4", "endOfRange": 5000}]}]}],
+	"featureFlags": [{"id": "f1", "key": "flag_1", "rolloutId": "r1", "experimentIds": ["e1"], "variables": []},
+		{"id": "f2", "key": "flag_2", "rolloutId": "", "experimentIds": [], "variables": []}]
+}`
+
+func newDiffTestConfig(t *testing.T, datafile string) ProjectConfig {
+	projectConfig, err := datafileprojectconfig.NewDatafileProjectConfig([]byte(datafile), logging.GetLogger("", "DatafileProjectConfig"))
+	assert.NoError(t, err)
+	return projectConfig
+}
+
+func TestDiffProjectConfigs(t *testing.T) {
+	diff := DiffProjectConfigs(newDiffTestConfig(t, diffOldDatafile), newDiffTestConfig(t, diffNewDatafile))
+
+	assert.Equal(t, notification.EntityChanges{Added: []string{"flag_2"}, Changed: []string{"flag_1"}}, diff.Flags)
+	assert.Equal(t, notification.EntityChanges{Added: []string{"e3"}, Removed: []string{"e2"}, Changed: []string{"e1"}}, diff.Experiments)
+	assert.Equal(t, notification.EntityChanges{Changed: []string{"r1"}}, diff.Rollouts)
+	assert.Equal(t, notification.EntityChanges{Added: []string{"a3"}, Removed: []string{"a2"}, Changed: []string{"a1"}}, diff.Audiences)
+	assert.Equal(t, []notification.TrafficAllocationChange{
+		{
+			RuleID:  "e1",
+			RuleKey: "exp_1",
+			Old:     []entities.Range{{EntityID: "v1", EndOfRange: 5000}, {EntityID: "v2", EndOfRange: 10000}},
+			New:     []entities.Range{{EntityID: "v1", EndOfRange: 2000}, {EntityID: "v2", EndOfRange: 4000}},
+		},
+		{
+			RuleID:    "rule1",
+			RuleKey:   "rule_1",
+			RolloutID: "r1",
+			Old:       []entities.Range{{EntityID: "v4", EndOfRange: 1000}},
+			New:       []entities.Range{{EntityID: "v4", EndOfRange: 5000}},
+		},
+	}, diff.TrafficAllocations)
+	assert.False(t, diff.IsEmpty())
+}
+
+func TestDiffProjectConfigsWithSharedRuleKeys(t *testing.T) {
+	// rule keys are only unique within a flag, so two flags can both have a "test" experiment and an "everyone" rule
+	const sharedKeysDatafile = `{
+		"revision": "%s", "version": "4",
+		"experiments": [
+			{"id": "e1", "key": "test", "layerId": "l1", "status": "Running", "audienceIds": [],
+				"variations": [{"id": "v1", "key": "a"}], "trafficAllocation": [{"entityId": "v1", "endOfRange": 10000}]},
+			{"id": "e2", "key": "test", "layerId": "l2", "status": "Running", "audienceIds": [],
+				"variations": [{"id": "v2", "key": "a"}], "trafficAllocation": [{"entityId": "v2", "endOfRange": %d}]}
+		],
+		"rollouts": [
+			{"id": "r1", "experiments": [{"id": "rule1", "key": "everyone", "layerId": "r1", "status": "Running", "audienceIds": [],
+				"variations": [{"id": "v3", "key": "on", "featureEnabled": true}], "trafficAllocation": [{"entityId": "v3", "endOfRange": 10000}]}]},
+			{"id": "r2", "experiments": [{"id": "rule2", "key": "everyone", "layerId": "r2", "status": "Running", "audienceIds": [],
+				"variations": [{"id": "v4", "key": "on", "featureEnabled": true}], "trafficAllocation": [{"entityId": "v4", "endOfRange": %d}]}]}
+		],
+		"featureFlags": [{"id": "f1", "key": "flag_1", "rolloutId": "r1", "experimentIds": ["e1"], "variables": []},
+			{"id": "f2", "key": "flag_2", "rolloutId": "r2", "experimentIds": ["e2"], "variables": []}]
+	}`
+	oldConfig := newDiffTestConfig(t, fmt.Sprintf(sharedKeysDatafile, "1", 10000, 10000))
+	newConfig := newDiffTestConfig(t, fmt.Sprintf(sharedKeysDatafile, "2", 5000, 2000))
+
+	diff := DiffProjectConfigs(oldConfig, newConfig)
+	assert.Equal(t, notification.EntityChanges{Changed: []string{"e2"}}, diff.Experiments)
+	assert.Equal(t, notification.EntityChanges{Changed: []string{"r2"}}, diff.Rollouts)
+	assert.Equal(t, []notification.TrafficAllocationChange{
+		{
+			RuleID:  "e2",
+			RuleKey: "test",
+			Old:     []entities.Range{{EntityID: "v2", EndOfRange: 10000}},
+			New:     []entities.Range{{EntityID: "v2", EndOfRange: 5000}},
+		},
+		{
+			RuleID:    "rule2",
+			RuleKey:   "everyone",
+			RolloutID: "r2",
+			Old:       []entities.Range{{EntityID: "v4", EndOfRange: 10000}},
+			New:       []entities.Range{{EntityID: "v4", EndOfRange: 2000}},
+		},
+	}, diff.TrafficAllocations)
+}
+
+func TestDiffProjectConfigsWithoutChanges(t *testing.T) {
+	diff := DiffProjectConfigs(newDiffTestConfig(t, diffOldDatafile), newDiffTestConfig(t, diffOldDatafile))
+	assert.True(t, diff.IsEmpty())
+}
+
+func TestDiffProjectConfigsWithoutOldConfig(t *testing.T) {
+	diff := DiffProjectConfigs(nil, newDiffTestConfig(t, diffOldDatafile))
+	assert.Equal(t, []string{"flag_1"}, diff.Flags.Added)
+	assert.Equal(t, []string{"e1", "e2"}, diff.Experiments.Added)
+	assert.Equal(t, []string{"r1"}, diff.Rollouts.Added)
+	assert.Equal(t, []string{"a1", "a2"}, diff.Audiences.Added)
+	assert.Empty(t, diff.TrafficAllocations)
+}
+
+func TestNewProjectConfigUpdateNotification(t *testing.T) {
+	oldConfig, newConfig := newDiffTestConfig(t, diffOldDatafile), newDiffTestConfig(t, diffNewDatafile)
+	projectConfigUpdateNotification := newProjectConfigUpdateNotification(oldConfig, newConfig)
+	assert.Equal(t, notification.ProjectConfigUpdate, projectConfigUpdateNotification.Type)
+	assert.Equal(t, "1", projectConfigUpdateNotification.OldRevision)
+	assert.Equal(t, "2", projectConfigUpdateNotification.Revision)
+	assert.Equal(t, DiffProjectConfigs(oldConfig, newConfig), projectConfigUpdateNotification.Diff)
+}
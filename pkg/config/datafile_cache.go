@@ -0,0 +1,106 @@
+/****************************************************************************
+ * Copyright 2026, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package config //
+package config
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// CachedDatafile is the last known good datafile persisted on disk
+type CachedDatafile struct {
+	Revision  string          `json:"revision"`
+	FetchedAt time.Time       `json:"fetchedAt"`
+	Datafile  json.RawMessage `json:"datafile,omitempty"`
+	// SignedDatafile keeps the exact bytes of a signed datafile, which Datafile would re-encode
+	SignedDatafile []byte `json:"signedDatafile,omitempty"`
+	Signature      []byte `json:"signature,omitempty"`
+}
+
+// Bytes returns the cached datafile
+func (c CachedDatafile) Bytes() []byte {
+	if len(c.SignedDatafile) > 0 {
+		return c.SignedDatafile
+	}
+	return c.Datafile
+}
+
+// DatafileCache persists the last known good datafile so that it can be used on a cold start when the CDN is unreachable
+type DatafileCache struct {
+	path string
+}
+
+// NewDatafileCache returns a new instance of the datafile cache stored at the given path
+func NewDatafileCache(path string) *DatafileCache {
+	return &DatafileCache{path: path}
+}
+
+// Load reads the cached datafile from disk
+func (c *DatafileCache) Load() (*CachedDatafile, error) {
+	content, err := ioutil.ReadFile(c.path)
+	if err != nil {
+		return nil, err
+	}
+	cachedDatafile := &CachedDatafile{}
+	if err := json.Unmarshal(content, cachedDatafile); err != nil {
+		return nil, err
+	}
+	return cachedDatafile, nil
+}
+
+// Save atomically replaces the cached datafile, readers never observe a partially written file
+func (c *DatafileCache) Save(datafile []byte, revision string, fetchedAt time.Time) error {
+	return c.SaveSigned(datafile, nil, revision, fetchedAt)
+}
+
+// SaveSigned atomically replaces the cached datafile, keeping its signature so that it can be verified when loaded
+func (c *DatafileCache) SaveSigned(datafile, signature []byte, revision string, fetchedAt time.Time) error {
+	cachedDatafile := CachedDatafile{Revision: revision, FetchedAt: fetchedAt}
+	if len(signature) > 0 {
+		cachedDatafile.SignedDatafile = datafile
+		cachedDatafile.Signature = signature
+	} else {
+		cachedDatafile.Datafile = datafile
+	}
+	content, err := json.Marshal(cachedDatafile)
+	if err != nil {
+		return err
+	}
+
+	tmpFile, err := ioutil.TempFile(filepath.Dir(c.path), filepath.Base(c.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name()) // no-op once renamed
+
+	if _, err := tmpFile.Write(content); err != nil {
+		tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Sync(); err != nil {
+		tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile.Name(), c.path)
+}
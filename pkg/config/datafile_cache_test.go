@@ -0,0 +1,57 @@
+/****************************************************************************
+ * Copyright 2026, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package config //
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDatafileCacheSaveAndLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "datafile_cache_test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	cache := NewDatafileCache(filepath.Join(dir, "datafile.cache"))
+	fetchedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	assert.NoError(t, cache.Save([]byte(`{"revision":"42","version":"4"}`), "42", fetchedAt))
+
+	cachedDatafile, err := cache.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "42", cachedDatafile.Revision)
+	assert.True(t, fetchedAt.Equal(cachedDatafile.FetchedAt))
+	assert.JSONEq(t, `{"revision":"42","version":"4"}`, string(cachedDatafile.Datafile))
+
+	// overwriting leaves no temporary files behind
+	assert.NoError(t, cache.Save([]byte(`{"revision":"43","version":"4"}`), "43", fetchedAt))
+	files, err := ioutil.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+}
+
+func TestDatafileCacheLoadMissing(t *testing.T) {
+	cache := NewDatafileCache(filepath.Join(os.TempDir(), "missing", "datafile.cache"))
+	cachedDatafile, err := cache.Load()
+	assert.Error(t, err)
+	assert.Nil(t, cachedDatafile)
+}
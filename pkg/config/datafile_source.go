@@ -0,0 +1,237 @@
+/****************************************************************************
+ * Copyright 2026, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package config //
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+
+	"github.com/WolffunService/experiment/pkg/logging"
+	"github.com/WolffunService/experiment/pkg/utils"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// DatafileSource loads the datafile from a location such as a URL or a local file
+type DatafileSource interface {
+	// Fetch returns the datafile, the validators of the current datafile are passed to avoid downloading it again
+	Fetch(validators DatafileValidators) (*FetchedDatafile, error)
+	// Name identifies the source in logs and in the config history
+	Name() string
+}
+
+// SignatureSource is implemented by the datafile sources which can load a detached signature of the datafile
+type SignatureSource interface {
+	FetchSignature() ([]byte, error)
+}
+
+// FetchedDatafile is the result of fetching the datafile from a DatafileSource
+type FetchedDatafile struct {
+	Datafile    []byte
+	NotModified bool               // the datafile did not change since the passed validators
+	Validators  DatafileValidators // cache validators to pass on the next fetch
+	Signature   []byte             // detached signature delivered with the datafile, if any
+	Source      DatafileSource     // the source which served the datafile
+	Priority    int                // position of the source in a ChainDatafileSource, 0 being the highest priority
+}
+
+// HTTPDatafileSource fetches the datafile from a URL
+type HTTPDatafileSource struct {
+	url       string
+	requester utils.Requester
+}
+
+// NewHTTPDatafileSource returns a source fetching the datafile from the given URL with the given requester
+func NewHTTPDatafileSource(url string, requester utils.Requester) *HTTPDatafileSource {
+	return &HTTPDatafileSource{url: url, requester: requester}
+}
+
+// Fetch downloads the datafile unless it was not modified since the given validators
+func (s *HTTPDatafileSource) Fetch(validators DatafileValidators) (*FetchedDatafile, error) {
+	var headers []utils.Header
+	if validators.LastModified != "" {
+		headers = append(headers, utils.Header{Name: ModifiedSince, Value: validators.LastModified})
+	}
+	if validators.ETag != "" {
+		headers = append(headers, utils.Header{Name: IfNoneMatch, Value: validators.ETag})
+	}
+	datafile, respHeaders, code, err := s.requester.Get(s.url, headers...)
+	if err != nil {
+		if code == http.StatusForbidden {
+			return nil, Err403Forbidden
+		}
+		return nil, err
+	}
+
+	fetchedDatafile := &FetchedDatafile{Datafile: datafile, NotModified: code == http.StatusNotModified, Source: s}
+	if respHeaders != nil {
+		fetchedDatafile.Validators = DatafileValidators{LastModified: respHeaders.Get(LastModified), ETag: respHeaders.Get(ETag)}
+		if signature := respHeaders.Get(DatafileSignatureHeader); signature != "" {
+			fetchedDatafile.Signature = []byte(signature)
+		}
+	}
+	return fetchedDatafile, nil
+}
+
+// FetchSignature downloads the sidecar signature file next to the datafile URL
+func (s *HTTPDatafileSource) FetchSignature() ([]byte, error) {
+	signature, _, _, err := s.requester.Get(s.url + DatafileSignatureSuffix)
+	return signature, err
+}
+
+// Name returns the URL of the datafile
+func (s *HTTPDatafileSource) Name() string {
+	return s.url
+}
+
+// FileDatafileSource reads the datafile from a local file, for instance one bundled with the application
+type FileDatafileSource struct {
+	path string
+}
+
+// NewFileDatafileSource returns a source reading the datafile from the given path
+func NewFileDatafileSource(path string) *FileDatafileSource {
+	return &FileDatafileSource{path: path}
+}
+
+// Fetch reads the datafile
+func (s *FileDatafileSource) Fetch(validators DatafileValidators) (*FetchedDatafile, error) {
+	datafile, err := ioutil.ReadFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+	return &FetchedDatafile{Datafile: datafile, Source: s}, nil
+}
+
+// FetchSignature reads the sidecar signature file next to the datafile
+func (s *FileDatafileSource) FetchSignature() ([]byte, error) {
+	return ioutil.ReadFile(s.path + DatafileSignatureSuffix)
+}
+
+// Name returns the path of the datafile
+func (s *FileDatafileSource) Name() string {
+	return s.path
+}
+
+// MemoryDatafileSource serves a datafile held in memory
+type MemoryDatafileSource struct {
+	name     string
+	lock     sync.RWMutex
+	datafile []byte
+}
+
+// NewMemoryDatafileSource returns a source serving the given datafile under the given name
+func NewMemoryDatafileSource(name string, datafile []byte) *MemoryDatafileSource {
+	return &MemoryDatafileSource{name: name, datafile: datafile}
+}
+
+// Set replaces the served datafile
+func (s *MemoryDatafileSource) Set(datafile []byte) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.datafile = datafile
+}
+
+// Fetch returns the datafile
+func (s *MemoryDatafileSource) Fetch(validators DatafileValidators) (*FetchedDatafile, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	if len(s.datafile) == 0 {
+		return nil, errors.New("no datafile in memory")
+	}
+	return &FetchedDatafile{Datafile: s.datafile, Source: s}, nil
+}
+
+// Name returns the name of the source
+func (s *MemoryDatafileSource) Name() string {
+	return s.name
+}
+
+// ChainDatafileSource fetches the datafile from the first of its sources which succeeds, the sources are given in order
+// of priority
+type ChainDatafileSource struct {
+	sources []DatafileSource
+	logger  logging.OptimizelyLogProducer
+}
+
+// NewChainDatafileSource returns a source trying the given sources in order
+func NewChainDatafileSource(logger logging.OptimizelyLogProducer, sources ...DatafileSource) *ChainDatafileSource {
+	return &ChainDatafileSource{sources: sources, logger: logger}
+}
+
+// Fetch returns the datafile from the first source which succeeds, or the errors of all of them, or Err403Forbidden
+// when the first source forbids the request. The validators are only passed to the first source, use
+// FetchWithValidators to pass each source the validators it issued.
+func (s *ChainDatafileSource) Fetch(validators DatafileValidators) (*FetchedDatafile, error) {
+	return s.fetch(func(priority int, source DatafileSource) DatafileValidators {
+		if priority == 0 {
+			return validators
+		}
+		return DatafileValidators{}
+	})
+}
+
+// FetchWithValidators returns the datafile from the first source which succeeds like Fetch, passing each source the
+// validators returned for it by validatorsOf
+func (s *ChainDatafileSource) FetchWithValidators(validatorsOf func(source DatafileSource) DatafileValidators) (*FetchedDatafile, error) {
+	return s.fetch(func(priority int, source DatafileSource) DatafileValidators {
+		return validatorsOf(source)
+	})
+}
+
+func (s *ChainDatafileSource) fetch(validatorsOf func(priority int, source DatafileSource) DatafileValidators) (*FetchedDatafile, error) {
+	var errs error
+	forbidden := false
+	for priority, source := range s.sources {
+		fetchedDatafile, err := source.Fetch(validatorsOf(priority, source))
+		if err == nil {
+			fetchedDatafile.Priority = priority
+			return fetchedDatafile, nil
+		}
+		if priority == 0 && err == Err403Forbidden {
+			forbidden = true
+		}
+		s.logger.Warning(fmt.Sprintf("Unable to fetch datafile from %s, trying the next source: %v", source.Name(), err))
+		errs = multierror.Append(errs, fmt.Errorf("%s: %v", source.Name(), err))
+	}
+	// callers compare the error to Err403Forbidden, so it is returned as is when the primary source forbids the request
+	if forbidden {
+		return nil, Err403Forbidden
+	}
+	if errs == nil {
+		return nil, errors.New("no datafile sources")
+	}
+	return nil, errs
+}
+
+// Name returns the names of the chained sources
+func (s *ChainDatafileSource) Name() string {
+	name := "chain"
+	for i, source := range s.sources {
+		if i == 0 {
+			name += ": "
+		} else {
+			name += ", "
+		}
+		name += source.Name()
+	}
+	return name
+}
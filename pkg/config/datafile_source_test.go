@@ -0,0 +1,214 @@
+/****************************************************************************
+ * Copyright 2026, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package config //
+package config
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/WolffunService/experiment/pkg/logging"
+	"github.com/WolffunService/experiment/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPDatafileSource(t *testing.T) {
+	mockRequester := new(MockRequester)
+	respHeaders := http.Header{}
+	respHeaders.Set(ETag, `"abc"`)
+	respHeaders.Set(DatafileSignatureHeader, "c2ln")
+	mockRequester.On("Get", []utils.Header{{Name: IfNoneMatch, Value: `"old"`}}).Return([]byte(`{"revision":"42","version": "4"}`), respHeaders, http.StatusOK, nil)
+
+	source := NewHTTPDatafileSource("https://localhost/datafile.json", mockRequester)
+	fetchedDatafile, err := source.Fetch(DatafileValidators{ETag: `"old"`})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte(`{"revision":"42","version": "4"}`), fetchedDatafile.Datafile)
+	assert.False(t, fetchedDatafile.NotModified)
+	assert.Equal(t, DatafileValidators{ETag: `"abc"`}, fetchedDatafile.Validators)
+	assert.Equal(t, []byte("c2ln"), fetchedDatafile.Signature)
+	assert.Equal(t, source, fetchedDatafile.Source)
+	assert.Equal(t, "https://localhost/datafile.json", source.Name())
+}
+
+func TestHTTPDatafileSourceForbidden(t *testing.T) {
+	mockRequester := new(MockRequester)
+	mockRequester.On("Get", []utils.Header(nil)).Return([]byte{}, http.Header{}, http.StatusForbidden, errors.New("forbidden"))
+
+	_, err := NewHTTPDatafileSource("https://localhost/datafile.json", mockRequester).Fetch(DatafileValidators{})
+	assert.Equal(t, Err403Forbidden, err)
+}
+
+func TestFileDatafileSource(t *testing.T) {
+	path, cleanup := tempDatafilePath(t)
+	defer cleanup()
+	source := NewFileDatafileSource(path)
+
+	_, err := source.Fetch(DatafileValidators{})
+	assert.Error(t, err)
+
+	writeDatafile(t, path, `{"revision":"42","version": "4"}`, time.Now())
+	assert.NoError(t, ioutil.WriteFile(path+DatafileSignatureSuffix, []byte("sig"), 0600))
+	fetchedDatafile, err := source.Fetch(DatafileValidators{})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte(`{"revision":"42","version": "4"}`), fetchedDatafile.Datafile)
+	signature, err := source.FetchSignature()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("sig"), signature)
+}
+
+func TestMemoryDatafileSource(t *testing.T) {
+	source := NewMemoryDatafileSource("memory", nil)
+	_, err := source.Fetch(DatafileValidators{})
+	assert.Error(t, err)
+
+	source.Set([]byte(`{"revision":"42","version": "4"}`))
+	fetchedDatafile, err := source.Fetch(DatafileValidators{})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte(`{"revision":"42","version": "4"}`), fetchedDatafile.Datafile)
+}
+
+func TestChainDatafileSource(t *testing.T) {
+	failingRequester := new(MockRequester)
+	failingRequester.On("Get", []utils.Header(nil)).Return([]byte{}, http.Header{}, http.StatusInternalServerError, errors.New("unavailable"))
+	cdn := NewHTTPDatafileSource("https://cdn/datafile.json", failingRequester)
+	mirror := NewMemoryDatafileSource("mirror", nil)
+	bundled := NewMemoryDatafileSource("bundled", []byte(`{"revision":"41","version": "4"}`))
+	chain := NewChainDatafileSource(logging.GetLogger("", "ChainDatafileSource"), cdn, mirror, bundled)
+	assert.Equal(t, "chain: https://cdn/datafile.json, mirror, bundled", chain.Name())
+
+	fetchedDatafile, err := chain.Fetch(DatafileValidators{})
+	assert.NoError(t, err)
+	assert.Equal(t, bundled, fetchedDatafile.Source)
+
+	mirror.Set([]byte(`{"revision":"42","version": "4"}`))
+	fetchedDatafile, err = chain.Fetch(DatafileValidators{})
+	assert.NoError(t, err)
+	assert.Equal(t, mirror, fetchedDatafile.Source)
+
+	_, err = NewChainDatafileSource(logging.GetLogger("", "ChainDatafileSource"), cdn).Fetch(DatafileValidators{})
+	assert.Contains(t, err.Error(), "https://cdn/datafile.json: unavailable")
+
+	var validated []string
+	fetchedDatafile, err = chain.FetchWithValidators(func(source DatafileSource) DatafileValidators {
+		validated = append(validated, source.Name())
+		return DatafileValidators{}
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, mirror, fetchedDatafile.Source)
+	assert.Equal(t, 1, fetchedDatafile.Priority)
+	assert.Equal(t, []string{"https://cdn/datafile.json", "mirror"}, validated)
+}
+
+func TestSyncConfigDoesNotFallBackToLowerPrioritySource(t *testing.T) {
+	path, cleanup := tempDatafilePath(t)
+	defer cleanup()
+	cdn := NewMemoryDatafileSource("cdn", nil)
+	bundled := NewMemoryDatafileSource("bundled", []byte(`{"revision":"10","version": "4"}`))
+
+	// before any config is loaded the bundled datafile is used while the CDN is down
+	configManager := NewPollingProjectConfigManager("datafile_sources", WithDatafileSources(cdn, bundled), WithDatafileCache(path))
+	assert.Equal(t, "10", currentRevision(configManager))
+
+	cdn.Set([]byte(`{"revision":"50","version": "4"}`))
+	configManager.SyncConfig()
+	assert.Equal(t, "50", currentRevision(configManager))
+
+	// but once the CDN served the config, its outages don't roll the config back to the bundled datafile
+	cdn.Set(nil)
+	configManager.SyncConfig()
+	assert.Equal(t, "50", currentRevision(configManager))
+	assert.Len(t, configManager.History(), 2)
+	cachedDatafile, err := NewDatafileCache(path).Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "50", cachedDatafile.Revision)
+}
+
+func TestSyncConfigFallsBackFromInitialDatafile(t *testing.T) {
+	failingRequester := new(MockRequester)
+	failingRequester.On("Get", []utils.Header(nil)).Return([]byte{}, http.Header{}, http.StatusServiceUnavailable, errors.New("unavailable"))
+	mirror := NewMemoryDatafileSource("mirror", []byte(`{"revision":"8","version": "4"}`))
+
+	configManager := NewPollingProjectConfigManager("datafile_sources",
+		WithInitialDatafile([]byte(`{"revision":"10","version": "4"}`)),
+		WithDatafileSources(NewHTTPDatafileSource("https://cdn/datafile.json", failingRequester), mirror))
+	assert.Equal(t, "10", currentRevision(configManager))
+
+	// the fallback source is tried on every poll while the CDN is down, but never rolls the config back
+	configManager.SyncConfig()
+	assert.Equal(t, "10", currentRevision(configManager))
+	mirror.Set([]byte(`{"revision":"12","version": "4"}`))
+	configManager.SyncConfig()
+	assert.Equal(t, "12", currentRevision(configManager))
+	failingRequester.AssertNumberOfCalls(t, "Get", 2)
+}
+
+func TestSyncConfigForbiddenThroughChain(t *testing.T) {
+	forbiddenRequester := new(MockRequester)
+	forbiddenRequester.On("Get", []utils.Header(nil)).Return([]byte{}, http.Header{}, http.StatusForbidden, errors.New("forbidden"))
+
+	configManager := NewPollingProjectConfigManager("datafile_sources", WithDatafileSources(
+		NewHTTPDatafileSource("https://cdn/datafile.json", forbiddenRequester), NewMemoryDatafileSource("mirror", nil)))
+	_, err := configManager.GetConfig()
+	assert.Equal(t, Err403Forbidden, err)
+}
+
+func TestSyncConfigKeepsValidatorsPerSource(t *testing.T) {
+	cdnRequester := new(MockRequester)
+	cdnRequester.On("Get", []utils.Header(nil)).Return([]byte{}, http.Header{}, http.StatusInternalServerError, errors.New("unavailable"))
+	mirrorHeaders := http.Header{}
+	mirrorHeaders.Set(ETag, `"mirror"`)
+	mirrorRequester := new(MockRequester)
+	mirrorRequester.On("Get", []utils.Header(nil)).Return([]byte(`{"revision":"42","version": "4"}`), mirrorHeaders, http.StatusOK, nil).Once()
+	mirrorRequester.On("Get", []utils.Header{{Name: IfNoneMatch, Value: `"mirror"`}}).Return([]byte{}, mirrorHeaders, http.StatusNotModified, nil)
+
+	configManager := NewPollingProjectConfigManager("datafile_sources", WithDatafileSources(
+		NewHTTPDatafileSource("https://cdn/datafile.json", cdnRequester), NewHTTPDatafileSource("https://mirror/datafile.json", mirrorRequester)))
+	configManager.SyncConfig()
+	assert.Equal(t, "42", currentRevision(configManager))
+	// the validators issued by the mirror are only sent back to the mirror
+	cdnRequester.AssertNumberOfCalls(t, "Get", 2)
+	cdnRequester.AssertExpectations(t)
+	mirrorRequester.AssertExpectations(t)
+	assert.Equal(t, DatafileValidators{}, configManager.GetDatafileValidators())
+}
+
+func TestSyncConfigRecordsDatafileSource(t *testing.T) {
+	failingRequester := new(MockRequester)
+	failingRequester.On("Get", []utils.Header(nil)).Return([]byte{}, http.Header{}, http.StatusInternalServerError, errors.New("unavailable"))
+	mirror := NewMemoryDatafileSource("mirror", []byte(`{"revision":"42","version": "4"}`))
+
+	configManager := NewPollingProjectConfigManager("datafile_sources", WithDatafileSources(
+		NewHTTPDatafileSource("https://cdn/datafile.json", failingRequester), mirror))
+	assert.Equal(t, "42", currentRevision(configManager))
+
+	mirror.Set([]byte(`{"revision":"43","version": "4"}`))
+	configManager.SyncConfig()
+	assert.Equal(t, "43", currentRevision(configManager))
+
+	history := configManager.History()
+	if assert.Len(t, history, 2) {
+		assert.Equal(t, "mirror", history[0].Source)
+		assert.Equal(t, "mirror", history[1].Source)
+	}
+
+	configManager = NewAsyncPollingProjectConfigManager("datafile_sources", WithInitialDatafile([]byte(`{"revision":"42","version": "4"}`)))
+	assert.Equal(t, InitialDatafileSourceName, configManager.History()[0].Source)
+}
@@ -0,0 +1,707 @@
+/****************************************************************************
+ * Copyright 2026, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package datafilebuilder builds datafiles programmatically for tests and local setups
+package datafilebuilder
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	datafileEntities "github.com/WolffunService/experiment/pkg/config/datafileprojectconfig/entities"
+	"github.com/WolffunService/experiment/pkg/entities"
+
+	"github.com/hashicorp/go-multierror"
+	jsoniter "github.com/json-iterator/go"
+)
+
+var json = jsoniter.ConfigCompatibleWithStandardLibrary
+
+const (
+	firstID             = 1000
+	datafileVersion     = "4"
+	defaultRevision     = "1"
+	groupPolicyRandom   = "random"
+	ruleVariationKey    = "on"
+	holdoutVariationKey = "off"
+	// maxTrafficRange is the end of range of a traffic allocation that covers all the traffic
+	maxTrafficRange = 10000
+)
+
+// Builder builds a datafile. Every entity gets a generated numeric ID, which can be looked up by key once the entity was added.
+type Builder struct {
+	datafile    datafileEntities.Datafile
+	flags       []*FlagBuilder
+	flagsByKey  map[string]*FlagBuilder
+	experiments []*ExperimentBuilder
+	groups      []*group
+	groupsByKey map[string]*group
+	layers      []*group
+	layersByKey map[string]*group
+	events      []event
+	holdouts    []holdout
+	ids         map[string]string
+	nextID      int
+	errs        []error
+}
+
+// FlagBuilder adds variables, experiments and rollout rules to a feature flag
+type FlagBuilder struct {
+	*Builder
+	flag        datafileEntities.FeatureFlag
+	experiments []*ExperimentBuilder
+	rules       []*ExperimentBuilder
+}
+
+// ExperimentBuilder adds variations, traffic allocation and audiences to an experiment or a rollout rule
+type ExperimentBuilder struct {
+	*FlagBuilder
+	experiment  datafileEntities.Experiment
+	isRule      bool
+	audiences   []string
+	variations  []*variation
+	percentages []float64
+	group       *group
+	layer       *group
+}
+
+type variation struct {
+	datafileEntities.Variation
+	values []variableValue
+}
+
+type variableValue struct {
+	key, value string
+}
+
+type group struct {
+	key         string
+	id          string
+	experiments []*ExperimentBuilder
+	percentages []float64
+}
+
+type holdout struct {
+	datafileEntities.Experiment
+	percentage float64
+	audiences  []string
+}
+
+type event struct {
+	datafileEntities.Event
+	experimentKeys []string
+}
+
+// New returns a builder of an empty version 4 datafile
+func New() *Builder {
+	b := &Builder{
+		flagsByKey:  map[string]*FlagBuilder{},
+		groupsByKey: map[string]*group{},
+		layersByKey: map[string]*group{},
+		ids:         map[string]string{},
+		nextID:      firstID,
+	}
+	b.datafile.Version = datafileVersion
+	b.datafile.Revision = defaultRevision
+	b.datafile.AccountID = b.newID("account")
+	b.datafile.ProjectID = b.newID("project")
+	return b
+}
+
+// Revision sets the revision of the datafile
+func (b *Builder) Revision(revision string) *Builder {
+	b.datafile.Revision = revision
+	return b
+}
+
+// SDKKey sets the SDK key of the datafile
+func (b *Builder) SDKKey(sdkKey string) *Builder {
+	b.datafile.SDKKey = sdkKey
+	return b
+}
+
+// SendFlagDecisions sets whether decision events are sent for rollout rules
+func (b *Builder) SendFlagDecisions(send bool) *Builder {
+	b.datafile.SendFlagDecisions = send
+	return b
+}
+
+// BotFiltering sets whether bot filtering is enabled
+func (b *Builder) BotFiltering(enabled bool) *Builder {
+	b.datafile.BotFiltering = enabled
+	return b
+}
+
+// AnonymizeIP sets whether the IP of users is anonymized
+func (b *Builder) AnonymizeIP(anonymize bool) *Builder {
+	b.datafile.AnonymizeIP = anonymize
+	return b
+}
+
+// Attribute adds an attribute
+func (b *Builder) Attribute(key string) *Builder {
+	if b.AttributeID(key) != "" {
+		b.errorf("attribute %q is already defined", key)
+		return b
+	}
+	b.datafile.Attributes = append(b.datafile.Attributes, datafileEntities.Attribute{ID: b.newID("attribute", key), Key: key})
+	return b
+}
+
+// Audience adds an audience, the conditions are either a JSON string or an already decoded condition tree such as
+// []interface{}{"and", map[string]interface{}{"type": "custom_attribute", "name": "age", "match": "gt", "value": 18}}
+func (b *Builder) Audience(name string, conditions interface{}) *Builder {
+	if b.AudienceID(name) != "" {
+		b.errorf("audience %q is already defined", name)
+		return b
+	}
+	b.datafile.TypedAudiences = append(b.datafile.TypedAudiences, datafileEntities.Audience{
+		ID:         b.newID("audience", name),
+		Name:       name,
+		Conditions: conditions,
+	})
+	return b
+}
+
+// Event adds a conversion event tracked by the experiments with the given keys
+func (b *Builder) Event(key string, experimentKeys ...string) *Builder {
+	if b.EventID(key) != "" {
+		b.errorf("event %q is already defined", key)
+		return b
+	}
+	b.events = append(b.events, event{
+		Event:          datafileEntities.Event{ID: b.newID("event", key), Key: key},
+		experimentKeys: experimentKeys,
+	})
+	return b
+}
+
+// Holdout adds a global holdout excluding the given percentage of the users matching any of the audiences, or of all
+// users without audiences, from every feature test and rollout. Its single variation is keyed "off".
+func (b *Builder) Holdout(key string, percentage float64, audienceNames ...string) *Builder {
+	if b.HoldoutID(key) != "" {
+		b.errorf("holdout %q is already defined", key)
+		return b
+	}
+	id := b.newID("holdout", key)
+	b.holdouts = append(b.holdouts, holdout{
+		Experiment: datafileEntities.Experiment{
+			ID:         id,
+			Key:        key,
+			LayerID:    id,
+			Status:     string(entities.ExperimentStatusRunning),
+			Variations: []datafileEntities.Variation{{ID: b.newID("variation", key, holdoutVariationKey), Key: holdoutVariationKey}},
+		},
+		percentage: percentage,
+		audiences:  audienceNames,
+	})
+	return b
+}
+
+// Flag adds a feature flag, or returns the builder of the flag if it was already added
+func (b *Builder) Flag(key string) *FlagBuilder {
+	if flag, ok := b.flagsByKey[key]; ok {
+		return flag
+	}
+	flag := &FlagBuilder{Builder: b}
+	flag.flag.ID = b.newID("flag", key)
+	flag.flag.Key = key
+	flag.flag.RolloutID = b.newID("rollout", key)
+	b.flags = append(b.flags, flag)
+	b.flagsByKey[key] = flag
+	return flag
+}
+
+// Variable adds a variable to the flag. JSON variables are encoded as strings with the json sub type like in datafiles
+// exported by Optimizely.
+func (f *FlagBuilder) Variable(key string, variableType entities.VariableType, defaultValue string) *FlagBuilder {
+	if f.VariableID(f.flag.Key, key) != "" {
+		f.errorf("variable %q of flag %q is already defined", key, f.flag.Key)
+		return f
+	}
+	variable := datafileEntities.Variable{
+		ID:           f.newID("variable", f.flag.Key, key),
+		Key:          key,
+		Type:         variableType,
+		DefaultValue: defaultValue,
+	}
+	if variableType == entities.JSON {
+		variable.Type = entities.String
+		variable.SubType = entities.JSON
+	}
+	f.flag.Variables = append(f.flag.Variables, variable)
+	return f
+}
+
+// Experiment adds an experiment to the flag
+func (f *FlagBuilder) Experiment(key string) *ExperimentBuilder {
+	experiment := f.newExperiment(key, false)
+	experiment.experiment.LayerID = f.newID("layer", key)
+	f.experiments = append(f.experiments, experiment)
+	return experiment
+}
+
+// Rule adds a targeted delivery rule to the rollout of the flag, rules are evaluated in the order they were added and
+// the last one is the "Everyone Else" rule. A rule without variations gets a single enabled variation with the key "on".
+func (f *FlagBuilder) Rule(key string) *ExperimentBuilder {
+	rule := f.newExperiment(key, true)
+	rule.experiment.LayerID = f.flag.RolloutID
+	f.rules = append(f.rules, rule)
+	return rule
+}
+
+func (f *FlagBuilder) newExperiment(key string, isRule bool) *ExperimentBuilder {
+	if f.ExperimentID(key) != "" {
+		f.errorf("experiment %q is already defined", key)
+	}
+	experiment := &ExperimentBuilder{FlagBuilder: f, isRule: isRule}
+	experiment.experiment.ID = f.newID("experiment", key)
+	experiment.experiment.Key = key
+	experiment.experiment.Status = string(entities.ExperimentStatusRunning)
+	experiment.experiment.ForcedVariations = map[string]string{}
+	f.Builder.experiments = append(f.Builder.experiments, experiment)
+	return experiment
+}
+
+// Variation adds a variation to the experiment
+func (e *ExperimentBuilder) Variation(key string, featureEnabled bool) *ExperimentBuilder {
+	if e.VariationID(e.experiment.Key, key) != "" {
+		e.errorf("variation %q of experiment %q is already defined", key, e.experiment.Key)
+		return e
+	}
+	e.variations = append(e.variations, &variation{Variation: datafileEntities.Variation{
+		ID:             e.newID("variation", e.experiment.Key, key),
+		Key:            key,
+		FeatureEnabled: featureEnabled,
+	}})
+	return e
+}
+
+// VariableValue sets the value of a variable of the flag in the last added variation
+func (e *ExperimentBuilder) VariableValue(variableKey, value string) *ExperimentBuilder {
+	if len(e.variations) == 0 {
+		e.errorf("experiment %q: variable %q set before adding a variation", e.experiment.Key, variableKey)
+		return e
+	}
+	last := e.variations[len(e.variations)-1]
+	last.values = append(last.values, variableValue{key: variableKey, value: value})
+	return e
+}
+
+// Traffic allocates the given percentages of the traffic to the variations in the order they were added, for instance
+// Traffic(50, 50) splits the traffic evenly between two variations and Traffic(5) rolls a rule out to 5% of the users.
+// The percentages must add up to at most 100. Without it the traffic is split evenly between all the variations.
+func (e *ExperimentBuilder) Traffic(percentages ...float64) *ExperimentBuilder {
+	e.percentages = percentages
+	return e
+}
+
+// Audiences targets the experiment to users in any of the audiences with the given names
+func (e *ExperimentBuilder) Audiences(names ...string) *ExperimentBuilder {
+	e.audiences = append(e.audiences, names...)
+	return e
+}
+
+// Status sets the status of the experiment, experiments are running by default
+func (e *ExperimentBuilder) Status(status entities.ExperimentStatus) *ExperimentBuilder {
+	e.experiment.Status = string(status)
+	return e
+}
+
+// Bandit opts the experiment into bandit allocation by the given algorithm, converting on the given event.
+func (e *ExperimentBuilder) Bandit(algorithm entities.BanditAlgorithm, epsilon float64, eventKey string) *ExperimentBuilder {
+	e.experiment.Bandit = &datafileEntities.Bandit{Algorithm: string(algorithm), Epsilon: epsilon, EventKey: eventKey}
+	return e
+}
+
+// BucketByRevision sets whether the experiment revision is part of the bucketing key, overriding the bucketer default.
+func (e *ExperimentBuilder) BucketByRevision(enabled bool) *ExperimentBuilder {
+	e.experiment.BucketByRevision = &enabled
+	return e
+}
+
+// Ramp adds a step to the ramp schedule of the experiment, letting the given percentage of its traffic in from the
+// given start time on. Experiments let no traffic in before the first step of their schedule.
+func (e *ExperimentBuilder) Ramp(startTime time.Time, percentage float64) *ExperimentBuilder {
+	e.experiment.RampSchedule = append(e.experiment.RampSchedule, datafileEntities.RampStep{StartTime: startTime, Percentage: percentage})
+	return e
+}
+
+// ForcedVariation forces the user with the given ID into a variation of the experiment
+func (e *ExperimentBuilder) ForcedVariation(userID, variationKey string) *ExperimentBuilder {
+	e.experiment.ForcedVariations[userID] = variationKey
+	return e
+}
+
+// Group adds the experiment to the mutually exclusive group with the given key and allocates the given percentage of
+// the traffic of the group to it. The group is created by the first experiment added to it.
+func (e *ExperimentBuilder) Group(groupKey string, percentage float64) *ExperimentBuilder {
+	if e.isRule {
+		e.errorf("rule %q can not be added to group %q", e.experiment.Key, groupKey)
+		return e
+	}
+	if e.group != nil {
+		e.errorf("experiment %q is already in group %q", e.experiment.Key, e.group.key)
+		return e
+	}
+	if e.layer != nil {
+		e.errorf("experiment %q is already in layer %q", e.experiment.Key, e.layer.key)
+		return e
+	}
+	g, ok := e.groupsByKey[groupKey]
+	if !ok {
+		g = &group{key: groupKey, id: e.newID("group", groupKey)}
+		e.groups = append(e.groups, g)
+		e.groupsByKey[groupKey] = g
+	}
+	g.experiments = append(g.experiments, e)
+	g.percentages = append(g.percentages, percentage)
+	e.group = g
+	return e
+}
+
+// Layer adds the experiment to the layer with the given key and allocates the given percentage of the traffic of the
+// layer to it. Experiments are mutually exclusive within a layer and orthogonal across layers. The layer is created by
+// the first experiment added to it.
+func (e *ExperimentBuilder) Layer(layerKey string, percentage float64) *ExperimentBuilder {
+	if e.isRule {
+		e.errorf("rule %q can not be added to layer %q", e.experiment.Key, layerKey)
+		return e
+	}
+	if e.group != nil {
+		e.errorf("experiment %q is already in group %q", e.experiment.Key, e.group.key)
+		return e
+	}
+	if e.layer != nil {
+		e.errorf("experiment %q is already in layer %q", e.experiment.Key, e.layer.key)
+		return e
+	}
+	l, ok := e.layersByKey[layerKey]
+	if !ok {
+		l = &group{key: layerKey, id: e.newID("layer", layerKey)}
+		e.layers = append(e.layers, l)
+		e.layersByKey[layerKey] = l
+	}
+	l.experiments = append(l.experiments, e)
+	l.percentages = append(l.percentages, percentage)
+	e.layer = l
+	e.experiment.LayerID = l.id
+	return e
+}
+
+// AttributeID returns the ID of the attribute with the given key, or an empty string if it was not added
+func (b *Builder) AttributeID(key string) string {
+	return b.ids[idKey("attribute", key)]
+}
+
+// AudienceID returns the ID of the audience with the given name, or an empty string if it was not added
+func (b *Builder) AudienceID(name string) string {
+	return b.ids[idKey("audience", name)]
+}
+
+// EventID returns the ID of the event with the given key, or an empty string if it was not added
+func (b *Builder) EventID(key string) string {
+	return b.ids[idKey("event", key)]
+}
+
+// FlagID returns the ID of the flag with the given key, or an empty string if it was not added
+func (b *Builder) FlagID(key string) string {
+	return b.ids[idKey("flag", key)]
+}
+
+// RolloutID returns the ID of the rollout of the flag with the given key, or an empty string if it was not added
+func (b *Builder) RolloutID(flagKey string) string {
+	return b.ids[idKey("rollout", flagKey)]
+}
+
+// VariableID returns the ID of a variable of a flag, or an empty string if it was not added
+func (b *Builder) VariableID(flagKey, variableKey string) string {
+	return b.ids[idKey("variable", flagKey, variableKey)]
+}
+
+// ExperimentID returns the ID of the experiment or rollout rule with the given key, or an empty string if it was not added
+func (b *Builder) ExperimentID(key string) string {
+	return b.ids[idKey("experiment", key)]
+}
+
+// VariationID returns the ID of a variation of an experiment, or an empty string if it was not added
+func (b *Builder) VariationID(experimentKey, variationKey string) string {
+	return b.ids[idKey("variation", experimentKey, variationKey)]
+}
+
+// HoldoutID returns the ID of the holdout with the given key, or an empty string if it was not added
+func (b *Builder) HoldoutID(key string) string {
+	return b.ids[idKey("holdout", key)]
+}
+
+// LayerID returns the ID of the layer with the given key, or an empty string if it was not added
+func (b *Builder) LayerID(key string) string {
+	return b.ids[idKey("layer", key)]
+}
+
+// GroupID returns the ID of the group with the given key, or an empty string if it was not added
+func (b *Builder) GroupID(key string) string {
+	return b.ids[idKey("group", key)]
+}
+
+// Build returns the JSON datafile, or all the errors found while building it
+func (b *Builder) Build() ([]byte, error) {
+	datafile, err := b.Datafile()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(datafile)
+}
+
+// MustBuild is like Build but panics if the datafile can not be built
+func (b *Builder) MustBuild() []byte {
+	datafile, err := b.Build()
+	if err != nil {
+		panic(err)
+	}
+	return datafile
+}
+
+// Datafile returns the datafile entity, or all the errors found while building it
+func (b *Builder) Datafile() (datafileEntities.Datafile, error) {
+	errs := append([]error{}, b.errs...)
+	errorf := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	datafile := b.datafile
+	datafile.Attributes = append([]datafileEntities.Attribute{}, b.datafile.Attributes...)
+	datafile.Audiences = []datafileEntities.Audience{}
+	datafile.TypedAudiences = append([]datafileEntities.Audience{}, b.datafile.TypedAudiences...)
+	datafile.Experiments = []datafileEntities.Experiment{}
+	datafile.Groups = []datafileEntities.Group{}
+	datafile.FeatureFlags = []datafileEntities.FeatureFlag{}
+	datafile.Rollouts = []datafileEntities.Rollout{}
+	datafile.Events = []datafileEntities.Event{}
+
+	for _, experiment := range b.experiments {
+		if experiment.isRule && len(experiment.variations) == 0 {
+			experiment.Variation(ruleVariationKey, true)
+		}
+	}
+
+	experiments := map[*ExperimentBuilder]datafileEntities.Experiment{}
+	for _, experiment := range b.experiments {
+		built, err := experiment.build()
+		if err != nil {
+			errs = append(errs, err)
+		}
+		experiments[experiment] = built
+	}
+
+	for _, flag := range b.flags {
+		featureFlag := flag.flag
+		featureFlag.ExperimentIDs = []string{}
+		featureFlag.Variables = append([]datafileEntities.Variable{}, flag.flag.Variables...)
+		for _, experiment := range flag.experiments {
+			featureFlag.ExperimentIDs = append(featureFlag.ExperimentIDs, experiment.experiment.ID)
+		}
+		rollout := datafileEntities.Rollout{ID: flag.flag.RolloutID, Experiments: []datafileEntities.Experiment{}}
+		for _, rule := range flag.rules {
+			rollout.Experiments = append(rollout.Experiments, experiments[rule])
+		}
+		datafile.FeatureFlags = append(datafile.FeatureFlags, featureFlag)
+		datafile.Rollouts = append(datafile.Rollouts, rollout)
+	}
+
+	for _, experiment := range b.experiments {
+		if !experiment.isRule && experiment.group == nil {
+			datafile.Experiments = append(datafile.Experiments, experiments[experiment])
+		}
+	}
+
+	for _, g := range b.groups {
+		built := datafileEntities.Group{ID: g.id, Policy: groupPolicyRandom, Experiments: []datafileEntities.Experiment{}}
+		experimentIDs := []string{}
+		for _, experiment := range g.experiments {
+			built.Experiments = append(built.Experiments, experiments[experiment])
+			experimentIDs = append(experimentIDs, experiment.experiment.ID)
+		}
+		allocation, err := TrafficAllocation(experimentIDs, g.percentages...)
+		if err != nil {
+			errorf("group %q: %v", g.key, err)
+		}
+		built.TrafficAllocation = allocation
+		datafile.Groups = append(datafile.Groups, built)
+	}
+
+	for _, l := range b.layers {
+		experimentIDs := []string{}
+		for _, experiment := range l.experiments {
+			experimentIDs = append(experimentIDs, experiment.experiment.ID)
+		}
+		allocation, err := TrafficAllocation(experimentIDs, l.percentages...)
+		if err != nil {
+			errorf("layer %q: %v", l.key, err)
+		}
+		datafile.Layers = append(datafile.Layers, datafileEntities.Layer{ID: l.id, Key: l.key, TrafficAllocation: allocation})
+	}
+
+	for _, e := range b.events {
+		built := e.Event
+		built.ExperimentIds = []string{}
+		for _, experimentKey := range e.experimentKeys {
+			experimentID := b.ExperimentID(experimentKey)
+			if experimentID == "" {
+				errorf("event %q: experiment %q is not defined", e.Key, experimentKey)
+				continue
+			}
+			built.ExperimentIds = append(built.ExperimentIds, experimentID)
+		}
+		datafile.Events = append(datafile.Events, built)
+	}
+
+	for _, h := range b.holdouts {
+		built := h.Experiment
+		built.AudienceIds = []string{}
+		for _, name := range h.audiences {
+			audienceID := b.AudienceID(name)
+			if audienceID == "" {
+				errorf("holdout %q: audience %q is not defined", h.Key, name)
+				continue
+			}
+			built.AudienceIds = append(built.AudienceIds, audienceID)
+		}
+		allocation, err := TrafficAllocation([]string{built.Variations[0].ID}, h.percentage)
+		if err != nil {
+			errorf("holdout %q: %v", h.Key, err)
+		}
+		built.TrafficAllocation = allocation
+		datafile.Holdouts = append(datafile.Holdouts, built)
+	}
+
+	if len(errs) > 0 {
+		return datafile, &multierror.Error{Errors: errs}
+	}
+	return datafile, nil
+}
+
+func (e *ExperimentBuilder) build() (datafileEntities.Experiment, error) {
+	experiment := e.experiment
+	var errs []error
+	errorf := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("experiment %q: "+format, append([]interface{}{experiment.Key}, args...)...))
+	}
+
+	experiment.AudienceIds = []string{}
+	for _, name := range e.audiences {
+		audienceID := e.AudienceID(name)
+		if audienceID == "" {
+			errorf("audience %q is not defined", name)
+			continue
+		}
+		experiment.AudienceIds = append(experiment.AudienceIds, audienceID)
+	}
+
+	experiment.ForcedVariations = map[string]string{}
+	for userID, variationKey := range e.experiment.ForcedVariations {
+		if e.VariationID(experiment.Key, variationKey) == "" {
+			errorf("forced variation %q is not defined", variationKey)
+		}
+		experiment.ForcedVariations[userID] = variationKey
+	}
+
+	for _, step := range experiment.RampSchedule {
+		if step.Percentage < 0 || step.Percentage > 100 {
+			errorf("ramp percentage %v is not between 0 and 100", step.Percentage)
+		}
+	}
+
+	if len(e.variations) == 0 {
+		errorf("no variations")
+	}
+	experiment.Variations = []datafileEntities.Variation{}
+	variationIDs := []string{}
+	for _, v := range e.variations {
+		built := v.Variation
+		built.Variables = []datafileEntities.VariationVariable{}
+		for _, value := range v.values {
+			variableID := e.VariableID(e.flag.Key, value.key)
+			if variableID == "" {
+				errorf("variable %q of flag %q is not defined", value.key, e.flag.Key)
+				continue
+			}
+			built.Variables = append(built.Variables, datafileEntities.VariationVariable{ID: variableID, Value: value.value})
+		}
+		experiment.Variations = append(experiment.Variations, built)
+		variationIDs = append(variationIDs, built.ID)
+	}
+
+	allocation, err := TrafficAllocation(variationIDs, e.percentages...)
+	if err != nil {
+		errorf("%v", err)
+	}
+	experiment.TrafficAllocation = allocation
+
+	if len(errs) > 0 {
+		return experiment, &multierror.Error{Errors: errs}
+	}
+	return experiment, nil
+}
+
+// TrafficAllocation converts percentages of the traffic to the cumulative ranges of a datafile traffic allocation,
+// in the order of the entity IDs. Without percentages the traffic is split evenly between all the entities.
+func TrafficAllocation(entityIDs []string, percentages ...float64) ([]datafileEntities.TrafficAllocation, error) {
+	if len(percentages) == 0 {
+		percentages = make([]float64, len(entityIDs))
+		for i := range percentages {
+			percentages[i] = 100 / float64(len(entityIDs))
+		}
+	}
+	if len(percentages) != len(entityIDs) {
+		return []datafileEntities.TrafficAllocation{}, fmt.Errorf("%d traffic percentages for %d entities", len(percentages), len(entityIDs))
+	}
+
+	allocation := []datafileEntities.TrafficAllocation{}
+	cumulative := 0.0
+	for i, percentage := range percentages {
+		if percentage < 0 {
+			return allocation, fmt.Errorf("negative traffic percentage %v", percentage)
+		}
+		cumulative += percentage
+		endOfRange := int(math.Round(cumulative * maxTrafficRange / 100))
+		if endOfRange > maxTrafficRange {
+			return allocation, fmt.Errorf("traffic percentages add up to %v, more than 100", cumulative)
+		}
+		allocation = append(allocation, datafileEntities.TrafficAllocation{EntityID: entityIDs[i], EndOfRange: endOfRange})
+	}
+	return allocation, nil
+}
+
+func (b *Builder) newID(kind string, keys ...string) string {
+	id := strconv.Itoa(b.nextID)
+	b.nextID++
+	if len(keys) > 0 {
+		b.ids[idKey(kind, keys...)] = id
+	}
+	return id
+}
+
+func (b *Builder) errorf(format string, args ...interface{}) {
+	b.errs = append(b.errs, fmt.Errorf(format, args...))
+}
+
+func idKey(kind string, keys ...string) string {
+	return kind + "/" + strings.Join(keys, "/")
+}
@@ -0,0 +1,196 @@
+/****************************************************************************
+ * Copyright 2026, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+package datafilebuilder
+
+import (
+	"testing"
+	"time"
+
+	"github.com/WolffunService/experiment/pkg/config/datafileprojectconfig"
+	datafileEntities "github.com/WolffunService/experiment/pkg/config/datafileprojectconfig/entities"
+	"github.com/WolffunService/experiment/pkg/entities"
+	"github.com/WolffunService/experiment/pkg/logging"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildRoundTripsThroughProjectConfig(t *testing.T) {
+	builder := New().Revision("7").SDKKey("builder_sdk_key").SendFlagDecisions(true).
+		Attribute("age").
+		Audience("adults", `["and", {"type": "custom_attribute", "name": "age", "match": "ge", "value": 18}]`).
+		Flag("checkout").
+		Variable("color", entities.String, "red").
+		Variable("config", entities.JSON, `{"a": 1}`).
+		Experiment("checkout_test").
+		Variation("control", false).
+		Variation("treatment", true).VariableValue("color", "blue").
+		Traffic(40, 60).
+		Audiences("adults").
+		ForcedVariation("qa_user", "treatment").
+		Flag("checkout").
+		Rule("adults_rollout").Audiences("adults").Traffic(25).
+		Flag("checkout").
+		Rule("everyone_else").Variation("off", false).
+		Event("purchase", "checkout_test")
+
+	jsonDatafile, err := builder.Build()
+	assert.NoError(t, err)
+	assert.Empty(t, datafileprojectconfig.Validate(jsonDatafile))
+
+	projectConfig, err := datafileprojectconfig.NewDatafileProjectConfig(jsonDatafile, logging.GetLogger("", "DatafileProjectConfig"))
+	assert.NoError(t, err)
+	assert.Equal(t, "7", projectConfig.GetRevision())
+	assert.Equal(t, "builder_sdk_key", projectConfig.GetSdkKey())
+	assert.True(t, projectConfig.SendFlagDecisions())
+	assert.Equal(t, builder.AttributeID("age"), projectConfig.GetAttributeID("age"))
+
+	feature, err := projectConfig.GetFeatureByKey("checkout")
+	assert.NoError(t, err)
+	assert.Equal(t, builder.FlagID("checkout"), feature.ID)
+	assert.Equal(t, entities.JSON, feature.VariableMap["config"].Type)
+	if assert.Len(t, feature.FeatureExperiments, 1) {
+		assert.Equal(t, builder.ExperimentID("checkout_test"), feature.FeatureExperiments[0].ID)
+	}
+	if assert.Len(t, feature.Rollout.Experiments, 2) {
+		rule := feature.Rollout.Experiments[0]
+		assert.Equal(t, "adults_rollout", rule.Key)
+		assert.Equal(t, builder.RolloutID("checkout"), rule.LayerID)
+		assert.Equal(t, []string{builder.AudienceID("adults")}, rule.AudienceIds)
+		assert.Equal(t, []entities.Range{{EntityID: builder.VariationID("adults_rollout", "on"), EndOfRange: 2500}}, rule.TrafficAllocation)
+		assert.True(t, rule.Variations[builder.VariationID("adults_rollout", "on")].FeatureEnabled)
+		assert.False(t, feature.Rollout.Experiments[1].Variations[builder.VariationID("everyone_else", "off")].FeatureEnabled)
+	}
+
+	experiment, err := projectConfig.GetExperimentByKey("checkout_test")
+	assert.NoError(t, err)
+	assert.True(t, experiment.IsRunning())
+	assert.Equal(t, []entities.Range{
+		{EntityID: builder.VariationID("checkout_test", "control"), EndOfRange: 4000},
+		{EntityID: builder.VariationID("checkout_test", "treatment"), EndOfRange: 10000},
+	}, experiment.TrafficAllocation)
+	assert.Equal(t, map[string]string{"qa_user": "treatment"}, experiment.Whitelist)
+	treatment := experiment.Variations[builder.VariationID("checkout_test", "treatment")]
+	assert.Equal(t, "blue", treatment.Variables[builder.VariableID("checkout", "color")].Value)
+
+	purchase, err := projectConfig.GetEventByKey("purchase")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{builder.ExperimentID("checkout_test")}, purchase.ExperimentIds)
+
+	audience, err := projectConfig.GetAudienceByID(builder.AudienceID("adults"))
+	assert.NoError(t, err)
+	assert.Equal(t, "adults", audience.Name)
+	assert.NotNil(t, audience.ConditionTree)
+}
+
+func TestBuildGroups(t *testing.T) {
+	builder := New()
+	builder.Flag("a").Experiment("exp_a").Variation("on", true).Group("checkout", 30)
+	builder.Flag("b").Experiment("exp_b").Variation("on", true).Group("checkout", 70)
+	builder.Flag("c").Experiment("exp_c").Variation("on", true)
+
+	jsonDatafile, err := builder.Build()
+	assert.NoError(t, err)
+	assert.Empty(t, datafileprojectconfig.Validate(jsonDatafile))
+
+	projectConfig, err := datafileprojectconfig.NewDatafileProjectConfig(jsonDatafile, logging.GetLogger("", "DatafileProjectConfig"))
+	assert.NoError(t, err)
+	group, err := projectConfig.GetGroupByID(builder.GroupID("checkout"))
+	assert.NoError(t, err)
+	assert.Equal(t, "random", group.Policy)
+	assert.Equal(t, []entities.Range{
+		{EntityID: builder.ExperimentID("exp_a"), EndOfRange: 3000},
+		{EntityID: builder.ExperimentID("exp_b"), EndOfRange: 10000},
+	}, group.TrafficAllocation)
+
+	experiment, _ := projectConfig.GetExperimentByKey("exp_b")
+	assert.Equal(t, builder.GroupID("checkout"), experiment.GroupID)
+	experiment, _ = projectConfig.GetExperimentByKey("exp_c")
+	assert.Empty(t, experiment.GroupID)
+}
+
+func TestBuildRampSchedule(t *testing.T) {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	builder := New()
+	builder.Flag("a").Rule("rollout").Ramp(start.Add(24*time.Hour), 5).Ramp(start, 1)
+
+	projectConfig, err := datafileprojectconfig.NewDatafileProjectConfig(builder.MustBuild(), logging.GetLogger("", "DatafileProjectConfig"))
+	assert.NoError(t, err)
+	feature, err := projectConfig.GetFeatureByKey("a")
+	assert.NoError(t, err)
+	// the steps are ordered by their start time
+	assert.Equal(t, []entities.RampStep{
+		{StartTime: start, Percentage: 1},
+		{StartTime: start.Add(24 * time.Hour), Percentage: 5},
+	}, feature.Rollout.Experiments[0].RampSchedule)
+
+	_, err = New().Flag("a").Rule("rollout").Ramp(start, 150).Build()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `experiment "rollout": ramp percentage 150 is not between 0 and 100`)
+	}
+}
+
+func TestBuildIsDeterministic(t *testing.T) {
+	build := func() []byte {
+		return New().Flag("a").Variable("v", entities.Integer, "1").Rule("r").Traffic(50).MustBuild()
+	}
+	assert.Equal(t, string(build()), string(build()))
+}
+
+func TestBuildErrors(t *testing.T) {
+	builder := New().Audience("adults", `[]`).Audience("adults", `[]`).
+		Flag("a").Experiment("exp_a").Traffic(50).Audiences("kids").
+		Flag("a").Experiment("exp_b").Variation("on", true).VariableValue("missing", "1").Traffic(60, 50).
+		Flag("a").Rule("rule").Group("g", 100).
+		Event("purchase", "exp_c")
+
+	_, err := builder.Build()
+	if assert.Error(t, err) {
+		for _, message := range []string{
+			`audience "adults" is already defined`,
+			`experiment "exp_a": audience "kids" is not defined`,
+			`experiment "exp_a": no variations`,
+			`experiment "exp_a": 1 traffic percentages for 0 entities`,
+			`experiment "exp_b": variable "missing" of flag "a" is not defined`,
+			`experiment "exp_b": 2 traffic percentages for 1 entities`,
+			`rule "rule" can not be added to group "g"`,
+			`event "purchase": experiment "exp_c" is not defined`,
+		} {
+			assert.Contains(t, err.Error(), message)
+		}
+	}
+	assert.Panics(t, func() { builder.MustBuild() })
+}
+
+func TestTrafficAllocation(t *testing.T) {
+	allocation, err := TrafficAllocation([]string{"a", "b", "c"})
+	assert.NoError(t, err)
+	assert.Equal(t, []datafileEntities.TrafficAllocation{{EntityID: "a", EndOfRange: 3333}, {EntityID: "b", EndOfRange: 6667}, {EntityID: "c", EndOfRange: 10000}}, allocation)
+
+	allocation, err = TrafficAllocation([]string{"a", "b"}, 12.5, 0.01)
+	assert.NoError(t, err)
+	assert.Equal(t, []datafileEntities.TrafficAllocation{{EntityID: "a", EndOfRange: 1250}, {EntityID: "b", EndOfRange: 1251}}, allocation)
+
+	_, err = TrafficAllocation([]string{"a", "b"}, 60, 50)
+	assert.EqualError(t, err, "traffic percentages add up to 110, more than 100")
+
+	_, err = TrafficAllocation([]string{"a"}, -1)
+	assert.EqualError(t, err, "negative traffic percentage -1")
+
+	allocation, err = TrafficAllocation(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, allocation)
+}
@@ -44,8 +44,10 @@ type DatafileProjectConfig struct {
 	experimentMap        map[string]entities.Experiment
 	featureMap           map[string]entities.Feature
 	groupMap             map[string]entities.Group
+	layerMap             map[string]entities.Layer
 	rollouts             []entities.Rollout
 	rolloutMap           map[string]entities.Rollout
+	holdouts             []entities.Experiment
 	anonymizeIP          bool
 	botFiltering         bool
 	sendFlagDecisions    bool
@@ -181,6 +183,11 @@ func (c DatafileProjectConfig) GetRolloutList() (rolloutList []entities.Rollout)
 	return c.rollouts
 }
 
+// GetHoldoutList returns the global holdouts in datafile order
+func (c DatafileProjectConfig) GetHoldoutList() []entities.Experiment {
+	return c.holdouts
+}
+
 // GetAudienceList returns an array of all the audiences
 func (c DatafileProjectConfig) GetAudienceList() (audienceList []entities.Audience) {
 	for _, audience := range c.audienceMap {
@@ -223,6 +230,15 @@ func (c DatafileProjectConfig) GetGroupByID(groupID string) (entities.Group, err
 	return entities.Group{}, fmt.Errorf(`group with ID "%s" not found`, groupID)
 }
 
+// GetLayerByID returns the layer with the given ID
+func (c DatafileProjectConfig) GetLayerByID(layerID string) (entities.Layer, error) {
+	if layer, ok := c.layerMap[layerID]; ok {
+		return layer, nil
+	}
+
+	return entities.Layer{}, fmt.Errorf(`layer with ID "%s" not found`, layerID)
+}
+
 // SendFlagDecisions determines whether impressions events are sent for ALL decision types
 func (c DatafileProjectConfig) SendFlagDecisions() bool {
 	return c.sendFlagDecisions
@@ -251,8 +267,10 @@ func NewDatafileProjectConfig(jsonDatafile []byte, logger logging.OptimizelyLogP
 	allExperiments := mappers.MergeExperiments(datafile.Experiments, datafile.Groups)
 	groupMap, experimentGroupMap := mappers.MapGroups(datafile.Groups)
 	experimentIDMap, experimentKeyMap := mappers.MapExperiments(allExperiments, experimentGroupMap)
+	layerMap := mappers.MapLayers(datafile.Layers, datafile.Experiments)
 
 	rollouts, rolloutMap := mappers.MapRollouts(datafile.Rollouts)
+	holdouts := mappers.MapHoldouts(datafile.Holdouts)
 	eventMap := mappers.MapEvents(datafile.Events)
 	mergedAudiences := append(datafile.TypedAudiences, datafile.Audiences...)
 	featureMap := mappers.MapFeatures(datafile.FeatureFlags, rolloutMap, experimentIDMap)
@@ -272,12 +290,14 @@ func NewDatafileProjectConfig(jsonDatafile []byte, logger logging.OptimizelyLogP
 		experimentKeyToIDMap: experimentKeyMap,
 		experimentMap:        experimentIDMap,
 		groupMap:             groupMap,
+		layerMap:             layerMap,
 		eventMap:             eventMap,
 		featureMap:           featureMap,
 		projectID:            datafile.ProjectID,
 		revision:             datafile.Revision,
 		rollouts:             rollouts,
 		rolloutMap:           rolloutMap,
+		holdouts:             holdouts,
 		sendFlagDecisions:    datafile.SendFlagDecisions,
 		flagVariationsMap:    flagVariationsMap,
 	}
@@ -465,6 +465,22 @@ func TestGetGroupByIDMissingIDError(t *testing.T) {
 	}
 }
 
+func TestGetLayerByID(t *testing.T) {
+	layer := entities.Layer{ID: "id", Salt: "id"}
+	config := &DatafileProjectConfig{
+		layerMap: map[string]entities.Layer{"id": layer},
+	}
+
+	actual, err := config.GetLayerByID("id")
+	assert.Nil(t, err)
+	assert.Equal(t, layer, actual)
+
+	_, err = config.GetLayerByID("other")
+	if assert.Error(t, err) {
+		assert.Equal(t, fmt.Errorf(`layer with ID "other" not found`), err)
+	}
+}
+
 func TestGetFlagVariationsMap(t *testing.T) {
 	absPath, _ := filepath.Abs("../../../test-data/decide-test-datafile.json")
 	datafile, err := ioutil.ReadFile(absPath)
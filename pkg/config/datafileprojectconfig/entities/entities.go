@@ -17,7 +17,11 @@
 // Package entities has entity definitions
 package entities
 
-import "github.com/WolffunService/experiment/pkg/entities"
+import (
+	"time"
+
+	"github.com/WolffunService/experiment/pkg/entities"
+)
 
 // Audience represents an Audience object from the Optimizely datafile
 type Audience struct {
@@ -44,6 +48,22 @@ type Experiment struct {
 	ForcedVariations   map[string]string   `json:"forcedVariations"`
 	AudienceConditions interface{}         `json:"audienceConditions"`
 	Revision           int                 `json:"revision"`
+	BucketByRevision   *bool               `json:"bucketByRevision,omitempty"`
+	Bandit             *Bandit             `json:"bandit,omitempty"`
+	RampSchedule       []RampStep          `json:"rampSchedule,omitempty"`
+}
+
+// RampStep represents a step of the ramp schedule of an Experiment, percentage ranges from 0 to 100
+type RampStep struct {
+	StartTime  time.Time `json:"startTime"`
+	Percentage float64   `json:"percentage"`
+}
+
+// Bandit represents the metadata opting an Experiment into multi-armed bandit allocation
+type Bandit struct {
+	Algorithm string  `json:"algorithm"`
+	Epsilon   float64 `json:"epsilon,omitempty"`
+	EventKey  string  `json:"eventKey,omitempty"`
 }
 
 // Group represents an Group object from the Optimizely datafile
@@ -54,6 +74,14 @@ type Group struct {
 	Experiments       []Experiment        `json:"experiments"`
 }
 
+// Layer represents a Layer object from the datafile, partitioning the experiments whose layerId references it
+type Layer struct {
+	ID                string              `json:"id"`
+	Key               string              `json:"key"`
+	Salt              string              `json:"salt,omitempty"`
+	TrafficAllocation []TrafficAllocation `json:"trafficAllocation,omitempty"`
+}
+
 // FeatureFlag represents a FeatureFlag object from the Optimizely datafile
 type FeatureFlag struct {
 	ID            string     `json:"id"`
@@ -114,6 +142,8 @@ type Datafile struct {
 	FeatureFlags      []FeatureFlag `json:"featureFlags"`
 	Events            []Event       `json:"events"`
 	Rollouts          []Rollout     `json:"rollouts"`
+	Holdouts          []Experiment  `json:"holdouts,omitempty"`
+	Layers            []Layer       `json:"layers,omitempty"`
 	TypedAudiences    []Audience    `json:"typedAudiences"`
 	Variables         []string      `json:"variables"`
 	AccountID         string        `json:"accountId"`
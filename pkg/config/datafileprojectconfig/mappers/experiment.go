@@ -18,6 +18,8 @@
 package mappers
 
 import (
+	"sort"
+
 	datafileEntities "github.com/WolffunService/experiment/pkg/config/datafileprojectconfig/entities"
 	"github.com/WolffunService/experiment/pkg/entities"
 )
@@ -54,29 +56,30 @@ func mapVariation(rawVariation datafileEntities.Variation) entities.Variation {
 	return variation
 }
 
-// Maps the raw experiment entity from the datafile into an SDK Experiment entity
-func mapExperiment(rawExperiment datafileEntities.Experiment) entities.Experiment {
-	var audienceConditionTree *entities.TreeNode
-	var err error
+// MapAudienceConditionTree builds the audience condition tree of a raw experiment from its audience conditions, or from
+// its audience IDs when it has no audience conditions
+func MapAudienceConditionTree(rawExperiment datafileEntities.Experiment) (audienceConditionTree *entities.TreeNode, err error) {
 	if rawExperiment.AudienceConditions == nil && len(rawExperiment.AudienceIds) > 0 {
-		audienceConditionTree, err = buildAudienceConditionTree(rawExperiment.AudienceIds)
-	} else {
-		switch audienceConditions := rawExperiment.AudienceConditions.(type) {
-		case []interface{}:
-			if len(audienceConditions) > 0 {
-				audienceConditionTree, err = buildAudienceConditionTree(audienceConditions)
-			}
-		case string:
-			if audienceConditions != "" {
-				audienceConditionTree, err = buildAudienceConditionTree([]string{audienceConditions})
-			}
-		default:
-		}
+		return buildAudienceConditionTree(rawExperiment.AudienceIds)
 	}
-	if err != nil {
-		// @TODO: handle error
-		func() {}() // cheat the linters
+	switch audienceConditions := rawExperiment.AudienceConditions.(type) {
+	case []interface{}:
+		if len(audienceConditions) > 0 {
+			return buildAudienceConditionTree(audienceConditions)
+		}
+	case string:
+		if audienceConditions != "" {
+			return buildAudienceConditionTree([]string{audienceConditions})
+		}
+	default:
 	}
+	return nil, nil
+}
+
+// Maps the raw experiment entity from the datafile into an SDK Experiment entity
+func mapExperiment(rawExperiment datafileEntities.Experiment) entities.Experiment {
+	// build errors are reported as malformed_condition issues by datafileprojectconfig.Validate
+	audienceConditionTree, _ := MapAudienceConditionTree(rawExperiment)
 
 	experiment := entities.Experiment{
 		AudienceIds:           rawExperiment.AudienceIds,
@@ -85,6 +88,8 @@ func mapExperiment(rawExperiment datafileEntities.Experiment) entities.Experimen
 		LayerID:               rawExperiment.LayerID,
 		Key:                   rawExperiment.Key,
 		Revision:              rawExperiment.Revision,
+		BucketByRevision:      rawExperiment.BucketByRevision,
+		Status:                entities.ExperimentStatus(rawExperiment.Status),
 		Variations:            make(map[string]entities.Variation),
 		VariationKeyToIDMap:   make(map[string]string),
 		TrafficAllocation:     make([]entities.Range, len(rawExperiment.TrafficAllocation)),
@@ -93,6 +98,24 @@ func mapExperiment(rawExperiment datafileEntities.Experiment) entities.Experimen
 		IsFeatureExperiment:   false,
 	}
 
+	if rawExperiment.Bandit != nil {
+		experiment.Bandit = &entities.Bandit{
+			Algorithm: entities.BanditAlgorithm(rawExperiment.Bandit.Algorithm),
+			Epsilon:   rawExperiment.Bandit.Epsilon,
+			EventKey:  rawExperiment.Bandit.EventKey,
+		}
+	}
+
+	if len(rawExperiment.RampSchedule) > 0 {
+		experiment.RampSchedule = make([]entities.RampStep, len(rawExperiment.RampSchedule))
+		for i, step := range rawExperiment.RampSchedule {
+			experiment.RampSchedule[i] = entities.RampStep(step)
+		}
+		sort.SliceStable(experiment.RampSchedule, func(i, j int) bool {
+			return experiment.RampSchedule[i].StartTime.Before(experiment.RampSchedule[j].StartTime)
+		})
+	}
+
 	for _, variation := range rawExperiment.Variations {
 		experiment.Variations[variation.ID] = mapVariation(variation)
 		experiment.VariationKeyToIDMap[variation.Key] = variation.ID
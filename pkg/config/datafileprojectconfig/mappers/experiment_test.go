@@ -30,6 +30,8 @@ func TestMapExperiments(t *testing.T) {
 		"audienceIds": ["31111"],
 		"id": "11111",
 		"key": "test_experiment_11111",
+		"status": "Paused",
+		"bucketByRevision": false,
 		"variations": [
 			{
 				"id": "21111",
@@ -68,12 +70,15 @@ func TestMapExperiments(t *testing.T) {
 	experimentGroupMap := map[string]string{"11111": "15"}
 
 	experimentsIDMap, experimentKeyMap := MapExperiments(rawExperiments, experimentGroupMap)
+	bucketByRevision := false
 	expectedExperiments := map[string]entities.Experiment{
 		"11111": {
-			AudienceIds: []string{"31111"},
-			ID:          "11111",
-			GroupID:     "15",
-			Key:         "test_experiment_11111",
+			AudienceIds:      []string{"31111"},
+			ID:               "11111",
+			GroupID:          "15",
+			Key:              "test_experiment_11111",
+			Status:           entities.ExperimentStatusPaused,
+			BucketByRevision: &bucketByRevision,
 			Variations: map[string]entities.Variation{
 				"21111": {
 					ID:             "21111",
@@ -236,3 +241,31 @@ func TestMapExperimentsAudienceIdsOnly(t *testing.T) {
 	experimentsIDMap, _ := MapExperiments([]datafileEntities.Experiment{rawExperiment}, map[string]string{})
 	assert.Equal(t, expectedExperiment.AudienceConditionTree, experimentsIDMap[rawExperiment.ID].AudienceConditionTree)
 }
+
+func TestMapExperimentsWithBandit(t *testing.T) {
+	rawExperiment := datafileEntities.Experiment{
+		ID:     "11111",
+		Key:    "test_experiment_11111",
+		Bandit: &datafileEntities.Bandit{Algorithm: "epsilon_greedy", Epsilon: 0.2, EventKey: "purchase"},
+	}
+
+	experimentsIDMap, _ := MapExperiments([]datafileEntities.Experiment{rawExperiment}, map[string]string{})
+	expectedBandit := &entities.Bandit{Algorithm: entities.BanditEpsilonGreedy, Epsilon: 0.2, EventKey: "purchase"}
+	assert.Equal(t, expectedBandit, experimentsIDMap["11111"].Bandit)
+}
+
+func TestMapAudienceConditionTree(t *testing.T) {
+	// audience conditions take precedence over audience IDs
+	audienceConditionTree, err := MapAudienceConditionTree(datafileEntities.Experiment{AudienceIds: []string{"11111"}, AudienceConditions: "11112"})
+	assert.NoError(t, err)
+	assert.Equal(t, &entities.TreeNode{Operator: "or", Nodes: []*entities.TreeNode{{Item: "11112"}}}, audienceConditionTree)
+
+	audienceConditionTree, err = MapAudienceConditionTree(datafileEntities.Experiment{AudienceIds: []string{"11111"}})
+	assert.NoError(t, err)
+	assert.Equal(t, &entities.TreeNode{Operator: "or", Nodes: []*entities.TreeNode{{Item: "11111"}}}, audienceConditionTree)
+
+	// experiments without audiences target everyone
+	audienceConditionTree, err = MapAudienceConditionTree(datafileEntities.Experiment{AudienceConditions: []interface{}{}})
+	assert.NoError(t, err)
+	assert.Nil(t, audienceConditionTree)
+}
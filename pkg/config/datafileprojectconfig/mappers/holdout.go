@@ -0,0 +1,38 @@
+/****************************************************************************
+ * Copyright 2026, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package mappers ...
+package mappers
+
+import (
+	datafileEntities "github.com/WolffunService/experiment/pkg/config/datafileprojectconfig/entities"
+	"github.com/WolffunService/experiment/pkg/entities"
+)
+
+// MapHoldouts maps the raw datafile holdouts to SDK Experiment entities. Holdout variations never enable a feature.
+func MapHoldouts(rawHoldouts []datafileEntities.Experiment) (holdoutList []entities.Experiment) {
+	holdoutList = []entities.Experiment{}
+	for _, rawHoldout := range rawHoldouts {
+		holdout := mapExperiment(rawHoldout)
+		for variationID, variation := range holdout.Variations {
+			variation.FeatureEnabled = false
+			holdout.Variations[variationID] = variation
+		}
+		holdoutList = append(holdoutList, holdout)
+	}
+
+	return holdoutList
+}
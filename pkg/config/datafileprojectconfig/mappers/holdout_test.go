@@ -0,0 +1,47 @@
+/****************************************************************************
+ * Copyright 2026, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+package mappers
+
+import (
+	"testing"
+
+	datafileEntities "github.com/WolffunService/experiment/pkg/config/datafileprojectconfig/entities"
+	"github.com/WolffunService/experiment/pkg/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapHoldouts(t *testing.T) {
+	rawHoldouts := []datafileEntities.Experiment{
+		{
+			ID:                "11111",
+			Key:               "global_holdout",
+			Status:            "Running",
+			Variations:        []datafileEntities.Variation{{ID: "21111", Key: "off", FeatureEnabled: true}},
+			TrafficAllocation: []datafileEntities.TrafficAllocation{{EntityID: "21111", EndOfRange: 500}},
+		},
+		{ID: "11112", Key: "second_holdout"},
+	}
+
+	holdoutList := MapHoldouts(rawHoldouts)
+	assert.Len(t, holdoutList, 2)
+	assert.Equal(t, "global_holdout", holdoutList[0].Key)
+	assert.False(t, holdoutList[0].Variations["21111"].FeatureEnabled)
+	assert.Equal(t, []entities.Range{{EntityID: "21111", EndOfRange: 500}}, holdoutList[0].TrafficAllocation)
+	assert.Equal(t, "second_holdout", holdoutList[1].Key)
+
+	assert.Equal(t, []entities.Experiment{}, MapHoldouts(nil))
+}
@@ -0,0 +1,73 @@
+/****************************************************************************
+ * Copyright 2026, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package mappers ...
+package mappers
+
+import (
+	"math"
+	"sort"
+
+	datafileEntities "github.com/WolffunService/experiment/pkg/config/datafileprojectconfig/entities"
+	"github.com/WolffunService/experiment/pkg/entities"
+)
+
+const maxTrafficRange = 10000
+
+// MapLayers maps the raw datafile layers to SDK Layer entities keyed by ID. A layer is salted with its ID unless it
+// has a salt of its own, and without a traffic allocation the traffic is split evenly between the experiments
+// referencing the layer, in ID order. Experiments of exclusion groups are never part of a layer.
+func MapLayers(rawLayers []datafileEntities.Layer, rawExperiments []datafileEntities.Experiment) (layerMap map[string]entities.Layer) {
+	layerMap = make(map[string]entities.Layer)
+	for _, rawLayer := range rawLayers {
+		layer := entities.Layer{
+			ID:                rawLayer.ID,
+			Key:               rawLayer.Key,
+			Salt:              rawLayer.Salt,
+			TrafficAllocation: []entities.Range{},
+		}
+		if layer.Salt == "" {
+			layer.Salt = layer.ID
+		}
+
+		for _, allocation := range rawLayer.TrafficAllocation {
+			layer.TrafficAllocation = append(layer.TrafficAllocation, entities.Range{EntityID: allocation.EntityID, EndOfRange: allocation.EndOfRange})
+		}
+		if len(rawLayer.TrafficAllocation) == 0 {
+			layer.TrafficAllocation = splitLayerTraffic(rawLayer.ID, rawExperiments)
+		}
+		layerMap[layer.ID] = layer
+	}
+
+	return layerMap
+}
+
+func splitLayerTraffic(layerID string, rawExperiments []datafileEntities.Experiment) []entities.Range {
+	experimentIDs := []string{}
+	for _, rawExperiment := range rawExperiments {
+		if rawExperiment.LayerID == layerID {
+			experimentIDs = append(experimentIDs, rawExperiment.ID)
+		}
+	}
+	sort.Strings(experimentIDs)
+
+	trafficAllocation := make([]entities.Range, len(experimentIDs))
+	for i, experimentID := range experimentIDs {
+		endOfRange := int(math.Round(float64(i+1) * maxTrafficRange / float64(len(experimentIDs))))
+		trafficAllocation[i] = entities.Range{EntityID: experimentID, EndOfRange: endOfRange}
+	}
+	return trafficAllocation
+}
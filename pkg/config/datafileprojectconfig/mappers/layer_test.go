@@ -0,0 +1,67 @@
+/****************************************************************************
+ * Copyright 2026, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+package mappers
+
+import (
+	"testing"
+
+	datafileEntities "github.com/WolffunService/experiment/pkg/config/datafileprojectconfig/entities"
+	"github.com/WolffunService/experiment/pkg/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapLayers(t *testing.T) {
+	rawLayers := []datafileEntities.Layer{
+		{
+			ID:                "31111",
+			Key:               "pricing",
+			Salt:              "pricing_v2",
+			TrafficAllocation: []datafileEntities.TrafficAllocation{{EntityID: "11111", EndOfRange: 3000}},
+		},
+		{ID: "31112", Key: "rewards"},
+	}
+	rawExperiments := []datafileEntities.Experiment{
+		{ID: "11113", LayerID: "31112"},
+		{ID: "11111", LayerID: "31111"},
+		{ID: "11114", LayerID: "31113"},
+		{ID: "11112", LayerID: "31112"},
+		{ID: "11115", LayerID: "31112"},
+	}
+
+	layerMap := MapLayers(rawLayers, rawExperiments)
+	expectedLayerMap := map[string]entities.Layer{
+		"31111": {
+			ID:                "31111",
+			Key:               "pricing",
+			Salt:              "pricing_v2",
+			TrafficAllocation: []entities.Range{{EntityID: "11111", EndOfRange: 3000}},
+		},
+		// without a traffic allocation the experiments of the layer split it evenly
+		"31112": {
+			ID:   "31112",
+			Key:  "rewards",
+			Salt: "31112",
+			TrafficAllocation: []entities.Range{
+				{EntityID: "11112", EndOfRange: 3333},
+				{EntityID: "11113", EndOfRange: 6667},
+				{EntityID: "11115", EndOfRange: 10000},
+			},
+		},
+	}
+	assert.Equal(t, expectedLayerMap, layerMap)
+	assert.Equal(t, entities.Group{ID: "pricing_v2", Policy: "random", TrafficAllocation: layerMap["31111"].TrafficAllocation}, layerMap["31111"].ExclusionGroup())
+}
@@ -0,0 +1,407 @@
+/****************************************************************************
+ * Copyright 2026, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package datafileprojectconfig //
+package datafileprojectconfig
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	datafileEntities "github.com/WolffunService/experiment/pkg/config/datafileprojectconfig/entities"
+	"github.com/WolffunService/experiment/pkg/config/datafileprojectconfig/mappers"
+	"github.com/WolffunService/experiment/pkg/entities"
+)
+
+// maxTrafficValue is the upper bound of a traffic allocation range
+const maxTrafficValue = 10000
+
+// Severity is the severity of a datafile validation issue
+type Severity string
+
+const (
+	// SeverityFatal - the datafile must not be used
+	SeverityFatal Severity = "fatal"
+	// SeverityWarning - the datafile is usable but some decisions may not behave as intended
+	SeverityWarning Severity = "warning"
+)
+
+// IssueCode identifies the kind of datafile validation issue
+type IssueCode string
+
+const (
+	// InvalidJSON - the datafile is not valid JSON
+	InvalidJSON IssueCode = "invalid_json"
+	// UnsupportedVersion - the datafile version is not supported by the SDK
+	UnsupportedVersion IssueCode = "unsupported_version"
+	// DanglingAudience - an audience ID is referenced but not defined
+	DanglingAudience IssueCode = "dangling_audience"
+	// DanglingExperiment - an experiment ID is referenced but not defined
+	DanglingExperiment IssueCode = "dangling_experiment"
+	// DanglingVariation - a variation ID is referenced but not defined
+	DanglingVariation IssueCode = "dangling_variation"
+	// DanglingRollout - a rollout ID is referenced but not defined
+	DanglingRollout IssueCode = "dangling_rollout"
+	// DanglingVariable - a variable ID is referenced but not defined
+	DanglingVariable IssueCode = "dangling_variable"
+	// UnsortedTrafficAllocation - traffic allocation ranges are not in ascending order
+	UnsortedTrafficAllocation IssueCode = "unsorted_traffic_allocation"
+	// TrafficAllocationOutOfRange - a traffic allocation range is outside of [0, 10000]
+	TrafficAllocationOutOfRange IssueCode = "traffic_allocation_out_of_range"
+	// DuplicateKey - two entities of the same kind share a key or ID
+	DuplicateKey IssueCode = "duplicate_key"
+	// VariableTypeMismatch - a variable value cannot be parsed as the variable type
+	VariableTypeMismatch IssueCode = "variable_type_mismatch"
+	// MalformedCondition - audience conditions cannot be parsed
+	MalformedCondition IssueCode = "malformed_condition"
+)
+
+// ValidationIssue describes a single problem found in a datafile
+type ValidationIssue struct {
+	Severity Severity
+	Code     IssueCode
+	Path     string // location of the offending entity, e.g. experiments[exp_key].trafficAllocation[1]
+	Message  string
+}
+
+func (i ValidationIssue) String() string {
+	return fmt.Sprintf("%s [%s] %s: %s", i.Severity, i.Code, i.Path, i.Message)
+}
+
+// ValidationError is returned when a datafile has fatal validation issues
+type ValidationError struct {
+	Issues []ValidationIssue
+}
+
+func (e *ValidationError) Error() string {
+	messages := []string{}
+	for _, issue := range e.Issues {
+		if issue.Severity == SeverityFatal {
+			messages = append(messages, issue.String())
+		}
+	}
+	return fmt.Sprintf("datafile has %d fatal validation issue(s): %s", len(messages), strings.Join(messages, "; "))
+}
+
+// HasFatal returns true if any of the given issues is fatal
+func HasFatal(issues []ValidationIssue) bool {
+	for _, issue := range issues {
+		if issue.Severity == SeverityFatal {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate lints the raw json datafile and returns all the issues found, an empty slice means the datafile is valid
+func Validate(jsonDatafile []byte) []ValidationIssue {
+	v := &validator{issues: []ValidationIssue{}}
+
+	datafile, err := Parse(jsonDatafile)
+	if err != nil {
+		v.add(SeverityFatal, InvalidJSON, "", "unable to parse datafile: %s", err.Error())
+		return v.issues
+	}
+
+	if _, ok := datafileVersions[datafile.Version]; !ok {
+		v.add(SeverityFatal, UnsupportedVersion, "version", "version %q of datafile is not supported", datafile.Version)
+	}
+
+	v.validate(datafile)
+	return v.issues
+}
+
+type validator struct {
+	issues        []ValidationIssue
+	audienceIDs   map[string]bool
+	experimentIDs map[string]bool
+	rolloutIDs    map[string]bool
+}
+
+func (v *validator) add(severity Severity, code IssueCode, path, format string, args ...interface{}) {
+	v.issues = append(v.issues, ValidationIssue{
+		Severity: severity,
+		Code:     code,
+		Path:     path,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *validator) validate(datafile *datafileEntities.Datafile) {
+	v.audienceIDs = map[string]bool{}
+	v.experimentIDs = map[string]bool{}
+	v.rolloutIDs = map[string]bool{}
+
+	attributeKeys := map[string]bool{}
+	for i, attribute := range datafile.Attributes {
+		v.checkDuplicate(attributeKeys, attribute.Key, fmt.Sprintf("attributes[%d]", i), "attribute key")
+	}
+
+	eventKeys := map[string]bool{}
+	for i, event := range datafile.Events {
+		v.checkDuplicate(eventKeys, event.Key, fmt.Sprintf("events[%d]", i), "event key")
+	}
+
+	// typed audiences take priority over audiences with the same ID, so duplicates are only checked within each list
+	for _, audiences := range []struct {
+		name string
+		list []datafileEntities.Audience
+	}{{"typedAudiences", datafile.TypedAudiences}, {"audiences", datafile.Audiences}} {
+		audienceIDs := map[string]bool{}
+		for i, audience := range audiences.list {
+			path := fmt.Sprintf("%s[%d]", audiences.name, i)
+			v.checkDuplicate(audienceIDs, audience.ID, path, "audience ID")
+			v.audienceIDs[audience.ID] = true
+			v.checkAudienceConditions(audience.Conditions, path+".conditions")
+		}
+	}
+
+	allExperiments := []datafileEntities.Experiment{}
+	allExperiments = append(allExperiments, datafile.Experiments...)
+	for _, group := range datafile.Groups {
+		allExperiments = append(allExperiments, group.Experiments...)
+	}
+	experimentKeys := map[string]bool{}
+	for _, experiment := range allExperiments {
+		// rule keys only need to be unique within a flag, so a shared experiment key only affects the legacy APIs
+		v.checkDuplicateWithSeverity(SeverityWarning, experimentKeys, experiment.Key, experimentPath(experiment), "experiment key")
+		v.checkDuplicate(v.experimentIDs, experiment.ID, experimentPath(experiment), "experiment ID")
+	}
+
+	for _, rollout := range datafile.Rollouts {
+		v.checkDuplicate(v.rolloutIDs, rollout.ID, fmt.Sprintf("rollouts[%s]", rollout.ID), "rollout ID")
+	}
+
+	variableTypes := map[string]entities.VariableType{}
+	flagKeys := map[string]bool{}
+	for _, flag := range datafile.FeatureFlags {
+		v.validateFeatureFlag(flag, flagKeys, variableTypes)
+	}
+
+	for _, experiment := range allExperiments {
+		v.validateExperiment(experiment, experimentPath(experiment), variableTypes)
+	}
+
+	for _, rollout := range datafile.Rollouts {
+		for i, experiment := range rollout.Experiments {
+			v.validateExperiment(experiment, fmt.Sprintf("rollouts[%s].experiments[%d]", rollout.ID, i), variableTypes)
+		}
+	}
+
+	for _, group := range datafile.Groups {
+		path := fmt.Sprintf("groups[%s]", group.ID)
+		groupExperimentIDs := map[string]bool{}
+		for _, experiment := range group.Experiments {
+			groupExperimentIDs[experiment.ID] = true
+		}
+		v.checkTrafficAllocation(group.TrafficAllocation, path+".trafficAllocation", groupExperimentIDs, DanglingExperiment, "experiment")
+	}
+
+	for i, event := range datafile.Events {
+		for _, experimentID := range event.ExperimentIds {
+			if !v.experimentIDs[experimentID] {
+				v.add(SeverityWarning, DanglingExperiment, fmt.Sprintf("events[%d]", i), "event %q references unknown experiment ID %q", event.Key, experimentID)
+			}
+		}
+	}
+}
+
+func (v *validator) validateFeatureFlag(flag datafileEntities.FeatureFlag, flagKeys map[string]bool, variableTypes map[string]entities.VariableType) {
+	path := fmt.Sprintf("featureFlags[%s]", flag.Key)
+	v.checkDuplicate(flagKeys, flag.Key, path, "feature flag key")
+
+	for _, experimentID := range flag.ExperimentIDs {
+		if !v.experimentIDs[experimentID] {
+			v.add(SeverityFatal, DanglingExperiment, path+".experimentIds", "unknown experiment ID %q", experimentID)
+		}
+	}
+	if flag.RolloutID != "" && !v.rolloutIDs[flag.RolloutID] {
+		v.add(SeverityFatal, DanglingRollout, path+".rolloutId", "unknown rollout ID %q", flag.RolloutID)
+	}
+
+	variableKeys := map[string]bool{}
+	for _, variable := range flag.Variables {
+		variablePath := fmt.Sprintf("%s.variables[%s]", path, variable.Key)
+		v.checkDuplicate(variableKeys, variable.Key, variablePath, "variable key")
+		variableType := variable.Type
+		if variable.Type == entities.String && variable.SubType == entities.JSON {
+			variableType = entities.JSON
+		}
+		variableTypes[variable.ID] = variableType
+		v.checkVariableValue(variable.DefaultValue, variableType, variablePath+".defaultValue")
+	}
+}
+
+func (v *validator) validateExperiment(experiment datafileEntities.Experiment, path string, variableTypes map[string]entities.VariableType) {
+	for _, audienceID := range experiment.AudienceIds {
+		if !v.audienceIDs[audienceID] {
+			v.add(SeverityFatal, DanglingAudience, path+".audienceIds", "unknown audience ID %q", audienceID)
+		}
+	}
+	if experiment.AudienceConditions != nil {
+		v.checkExperimentAudienceConditions(experiment.AudienceConditions, path+".audienceConditions")
+	}
+	if _, err := mappers.MapAudienceConditionTree(experiment); err != nil {
+		v.add(SeverityFatal, MalformedCondition, path+".audienceConditions", "unable to build audience condition tree: %s", err.Error())
+	}
+
+	variationIDs := map[string]bool{}
+	variationKeys := map[string]bool{}
+	for _, variation := range experiment.Variations {
+		variationPath := fmt.Sprintf("%s.variations[%s]", path, variation.Key)
+		v.checkDuplicate(variationKeys, variation.Key, variationPath, "variation key")
+		v.checkDuplicate(variationIDs, variation.ID, variationPath, "variation ID")
+		for _, variable := range variation.Variables {
+			variableType, ok := variableTypes[variable.ID]
+			if !ok {
+				v.add(SeverityWarning, DanglingVariable, variationPath+".variables", "unknown variable ID %q", variable.ID)
+				continue
+			}
+			v.checkVariableValue(variable.Value, variableType, fmt.Sprintf("%s.variables[%s]", variationPath, variable.ID))
+		}
+	}
+
+	v.checkTrafficAllocation(experiment.TrafficAllocation, path+".trafficAllocation", variationIDs, DanglingVariation, "variation")
+}
+
+func (v *validator) checkDuplicate(seen map[string]bool, value, path, kind string) {
+	v.checkDuplicateWithSeverity(SeverityFatal, seen, value, path, kind)
+}
+
+func (v *validator) checkDuplicateWithSeverity(severity Severity, seen map[string]bool, value, path, kind string) {
+	if seen[value] {
+		v.add(severity, DuplicateKey, path, "duplicate %s %q", kind, value)
+		return
+	}
+	seen[value] = true
+}
+
+func (v *validator) checkTrafficAllocation(allocations []datafileEntities.TrafficAllocation, path string, entityIDs map[string]bool, code IssueCode, kind string) {
+	previousEndOfRange := 0
+	for i, allocation := range allocations {
+		rangePath := fmt.Sprintf("%s[%d]", path, i)
+		if allocation.EndOfRange < 0 || allocation.EndOfRange > maxTrafficValue {
+			v.add(SeverityFatal, TrafficAllocationOutOfRange, rangePath, "endOfRange %d is outside of [0, %d]", allocation.EndOfRange, maxTrafficValue)
+		}
+		if allocation.EndOfRange < previousEndOfRange {
+			v.add(SeverityFatal, UnsortedTrafficAllocation, rangePath, "endOfRange %d is lower than the previous endOfRange %d", allocation.EndOfRange, previousEndOfRange)
+		}
+		previousEndOfRange = allocation.EndOfRange
+
+		// an empty entity ID is used for unallocated traffic
+		if allocation.EntityID != "" && !entityIDs[allocation.EntityID] {
+			v.add(SeverityFatal, code, rangePath, "unknown %s ID %q", kind, allocation.EntityID)
+		}
+	}
+}
+
+func (v *validator) checkVariableValue(value string, variableType entities.VariableType, path string) {
+	var err error
+	switch variableType {
+	case entities.Boolean:
+		_, err = strconv.ParseBool(value)
+	case entities.Double:
+		_, err = strconv.ParseFloat(value, 64)
+	case entities.Integer:
+		_, err = strconv.Atoi(value)
+	case entities.JSON:
+		var parsed map[string]interface{}
+		err = json.Unmarshal([]byte(value), &parsed)
+	case entities.String:
+	default:
+		v.add(SeverityWarning, VariableTypeMismatch, path, "unknown variable type %q", variableType)
+		return
+	}
+	if err != nil {
+		v.add(SeverityFatal, VariableTypeMismatch, path, "value %q is not a valid %s", value, variableType)
+	}
+}
+
+// checkAudienceConditions checks the conditions of an audience, which are either a JSON string or an already decoded tree
+func (v *validator) checkAudienceConditions(conditions interface{}, path string) {
+	if serialized, ok := conditions.(string); ok {
+		var parsed interface{}
+		if err := json.Unmarshal([]byte(serialized), &parsed); err != nil {
+			v.add(SeverityFatal, MalformedCondition, path, "unable to parse conditions: %s", err.Error())
+			return
+		}
+		conditions = parsed
+	}
+
+	switch typed := conditions.(type) {
+	case map[string]interface{}:
+		v.checkLeafCondition(typed, path)
+	case []interface{}:
+		for i, node := range typed {
+			switch item := node.(type) {
+			case string:
+				if i != 0 || !isOperator(item) {
+					v.add(SeverityFatal, MalformedCondition, path, "unexpected operator %q", item)
+				}
+			case []interface{}, map[string]interface{}:
+				v.checkAudienceConditions(item, fmt.Sprintf("%s[%d]", path, i))
+			default:
+				v.add(SeverityFatal, MalformedCondition, path, "unexpected condition node %v", item)
+			}
+		}
+	default:
+		v.add(SeverityFatal, MalformedCondition, path, "unexpected conditions %v", conditions)
+	}
+}
+
+func (v *validator) checkLeafCondition(condition map[string]interface{}, path string) {
+	for _, field := range []string{"name", "type"} {
+		if value, ok := condition[field].(string); !ok || value == "" {
+			v.add(SeverityFatal, MalformedCondition, path, "condition is missing %q", field)
+		}
+	}
+}
+
+// checkExperimentAudienceConditions checks a tree of operators and audience IDs
+func (v *validator) checkExperimentAudienceConditions(conditions interface{}, path string) {
+	switch typed := conditions.(type) {
+	case string:
+		if typed != "" && !v.audienceIDs[typed] {
+			v.add(SeverityFatal, DanglingAudience, path, "unknown audience ID %q", typed)
+		}
+	case []interface{}:
+		for i, node := range typed {
+			if item, ok := node.(string); ok && isOperator(item) {
+				if i != 0 {
+					v.add(SeverityFatal, MalformedCondition, path, "unexpected operator %q", item)
+				}
+				continue
+			}
+			v.checkExperimentAudienceConditions(node, path)
+		}
+	default:
+		v.add(SeverityFatal, MalformedCondition, path, "unexpected audience condition %v", conditions)
+	}
+}
+
+func isOperator(value string) bool {
+	switch value {
+	case "and", "or", "not":
+		return true
+	default:
+		return false
+	}
+}
+
+func experimentPath(experiment datafileEntities.Experiment) string {
+	return fmt.Sprintf("experiments[%s]", experiment.Key)
+}
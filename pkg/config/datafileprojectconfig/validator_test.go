@@ -0,0 +1,120 @@
+/****************************************************************************
+ * Copyright 2026, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package datafileprojectconfig //
+package datafileprojectconfig
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const validDatafile = `{
+	"version": "4",
+	"revision": "1",
+	"audiences": [{"id": "a1", "name": "adults", "conditions": "[\"and\", {\"name\": \"age\", \"type\": \"custom_attribute\", \"match\": \"ge\", \"value\": 18}]"}],
+	"experiments": [{
+		"id": "e1", "key": "exp_1", "status": "Running", "audienceIds": ["a1"], "audienceConditions": ["or", "a1"],
+		"variations": [{"id": "v1", "key": "on", "variables": [{"id": "var1", "value": "5"}]}, {"id": "v2", "key": "off"}],
+		"trafficAllocation": [{"entityId": "v1", "endOfRange": 5000}, {"entityId": "v2", "endOfRange": 10000}]
+	}],
+	"rollouts": [{"id": "r1", "experiments": [{"id": "e2", "key": "rule_1", "variations": [{"id": "v3", "key": "on"}], "trafficAllocation": [{"entityId": "v3", "endOfRange": 10000}]}]}],
+	"featureFlags": [{"id": "f1", "key": "flag_1", "rolloutId": "r1", "experimentIds": ["e1"], "variables": [{"id": "var1", "key": "count", "type": "integer", "defaultValue": "1"}]}],
+	"events": [{"id": "ev1", "key": "purchase", "experimentIds": ["e1"]}]
+}`
+
+func TestValidateValidDatafile(t *testing.T) {
+	issues := Validate([]byte(validDatafile))
+	assert.Empty(t, issues)
+	assert.False(t, HasFatal(issues))
+}
+
+func TestValidateInvalidJSON(t *testing.T) {
+	issues := Validate([]byte(`{"version":`))
+	if assert.Len(t, issues, 1) {
+		assert.Equal(t, SeverityFatal, issues[0].Severity)
+		assert.Equal(t, InvalidJSON, issues[0].Code)
+	}
+}
+
+func TestValidateUnsupportedVersion(t *testing.T) {
+	issues := Validate([]byte(`{"version": "2"}`))
+	if assert.Len(t, issues, 1) {
+		assert.Equal(t, UnsupportedVersion, issues[0].Code)
+	}
+}
+
+func TestValidateReportsIssues(t *testing.T) {
+	datafile := `{
+		"version": "4",
+		"audiences": [{"id": "a1", "name": "broken", "conditions": "[\"and\", {\"match\": \"gt\""}],
+		"experiments": [
+			{
+				"id": "e1", "key": "exp_1", "audienceIds": ["missing_audience"],
+				"variations": [{"id": "v1", "key": "on", "variables": [{"id": "var1", "value": "abc"}]}, {"id": "v2", "key": "on"}],
+				"trafficAllocation": [{"entityId": "v1", "endOfRange": 6000}, {"entityId": "v9", "endOfRange": 5000}, {"entityId": "v2", "endOfRange": 10001}]
+			},
+			{"id": "e1", "key": "exp_2", "audienceConditions": ["and", "a1", ["or", "a2"]]}
+		],
+		"featureFlags": [{"id": "f1", "key": "flag_1", "rolloutId": "r9", "experimentIds": ["e9"], "variables": [{"id": "var1", "key": "count", "type": "integer", "defaultValue": "1.5"}]}]
+	}`
+
+	issues := Validate([]byte(datafile))
+	assert.True(t, HasFatal(issues))
+
+	codes := map[IssueCode]int{}
+	for _, issue := range issues {
+		codes[issue.Code]++
+	}
+	assert.Equal(t, map[IssueCode]int{
+		MalformedCondition:          1,
+		DanglingAudience:            2,
+		DanglingExperiment:          1,
+		DanglingRollout:             1,
+		DanglingVariation:           1,
+		DuplicateKey:                2,
+		UnsortedTrafficAllocation:   1,
+		TrafficAllocationOutOfRange: 1,
+		VariableTypeMismatch:        2,
+	}, codes)
+}
+
+func TestValidateDuplicateExperimentKeyIsWarning(t *testing.T) {
+	datafile := `{"version": "4", "experiments": [{"id": "e1", "key": "rule"}, {"id": "e2", "key": "rule"}]}`
+	issues := Validate([]byte(datafile))
+	if assert.Len(t, issues, 1) {
+		assert.Equal(t, SeverityWarning, issues[0].Severity)
+		assert.Equal(t, DuplicateKey, issues[0].Code)
+		assert.Equal(t, "experiments[rule]", issues[0].Path)
+	}
+	assert.False(t, HasFatal(issues))
+}
+
+func TestValidateTestDatafile(t *testing.T) {
+	datafile, err := ioutil.ReadFile("test/100_entities.json")
+	assert.NoError(t, err)
+	assert.Empty(t, Validate(datafile))
+}
+
+func TestValidationError(t *testing.T) {
+	err := &ValidationError{Issues: []ValidationIssue{
+		{Severity: SeverityWarning, Code: DanglingVariable, Path: "p1", Message: "ignored"},
+		{Severity: SeverityFatal, Code: DuplicateKey, Path: "p2", Message: `duplicate feature flag key "f"`},
+	}}
+	assert.Equal(t, `datafile has 1 fatal validation issue(s): fatal [duplicate_key] p2: duplicate feature flag key "f"`, err.Error())
+}
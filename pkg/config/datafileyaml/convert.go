@@ -0,0 +1,417 @@
+/****************************************************************************
+ * Copyright 2026, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package datafileyaml converts a compact YAML description of flags to a datafile
+package datafileyaml
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/WolffunService/experiment/pkg/config/datafilebuilder"
+	"github.com/WolffunService/experiment/pkg/entities"
+
+	jsoniter "github.com/json-iterator/go"
+	"gopkg.in/yaml.v2"
+)
+
+var json = jsoniter.ConfigCompatibleWithStandardLibrary
+
+// Document is the YAML authoring format of a datafile, for instance
+//
+//	revision: "3"
+//	audiences:
+//	  adults:
+//	    conditions:
+//	      - {attribute: age, match: ge, value: 18}
+//	flags:
+//	  checkout:
+//	    variables:
+//	      color: {type: string, default: red}
+//	    experiments:
+//	      - key: checkout_test
+//	        variations:
+//	          - {key: control, percentage: 50}
+//	          - {key: treatment, percentage: 50, variables: {color: blue}}
+//	    rules:
+//	      - key: adults
+//	        audiences: [adults]
+//	        percentage: 20
+//	      - key: everyone_else
+//	        enabled: false
+//
+// Audiences and flags are added in the order of their keys, so the generated IDs are stable.
+type Document struct {
+	Revision          string              `yaml:"revision"`
+	SDKKey            string              `yaml:"sdkKey"`
+	SendFlagDecisions bool                `yaml:"sendFlagDecisions"`
+	Audiences         map[string]Audience `yaml:"audiences"`
+	Flags             map[string]Flag     `yaml:"flags"`
+}
+
+// Audience matches users for which all (the default) or any of the conditions match
+type Audience struct {
+	Match      string      `yaml:"match"`
+	Conditions []Condition `yaml:"conditions"`
+}
+
+// Condition matches a user attribute, the match type defaults to exact
+type Condition struct {
+	Attribute string      `yaml:"attribute"`
+	Match     string      `yaml:"match"`
+	Value     interface{} `yaml:"value"`
+}
+
+// Flag has variables, experiments and targeted delivery rules, which are evaluated in order
+type Flag struct {
+	Variables   map[string]Variable `yaml:"variables"`
+	Experiments []Experiment        `yaml:"experiments"`
+	Rules       []Rule              `yaml:"rules"`
+}
+
+// Variable is a flag variable of type boolean, integer, double, string or json. The default value of a json variable
+// may be written as YAML.
+type Variable struct {
+	Type    string      `yaml:"type"`
+	Default interface{} `yaml:"default"`
+}
+
+// Rule enables (the default) or disables the flag for a percentage of the users in any of the audiences. The
+// percentage defaults to 100 and the key to the flag key followed by the position of the rule.
+type Rule struct {
+	Key        string                 `yaml:"key"`
+	Audiences  []string               `yaml:"audiences"`
+	Percentage *float64               `yaml:"percentage"`
+	Enabled    *bool                  `yaml:"enabled"`
+	Variables  map[string]interface{} `yaml:"variables"`
+}
+
+// Experiment splits the users in any of the audiences between its variations
+type Experiment struct {
+	Key        string      `yaml:"key"`
+	Status     string      `yaml:"status"`
+	Audiences  []string    `yaml:"audiences"`
+	Variations []Variation `yaml:"variations"`
+}
+
+// Variation of an experiment, either all or none of the variations of an experiment have a percentage, without
+// percentages the traffic is split evenly. Variations enable the flag by default.
+type Variation struct {
+	Key        string                 `yaml:"key"`
+	Percentage *float64               `yaml:"percentage"`
+	Enabled    *bool                  `yaml:"enabled"`
+	Variables  map[string]interface{} `yaml:"variables"`
+}
+
+// Convert converts a YAML document to a JSON datafile. The error lists all the problems found in the document with
+// their line, it is of type Errors unless the datafile can not be built.
+func Convert(document []byte) ([]byte, error) {
+	var doc Document
+	if err := yaml.UnmarshalStrict(document, &doc); err != nil {
+		return nil, yamlErrors(err)
+	}
+
+	c := &converter{
+		builder:        datafilebuilder.New(),
+		locator:        newLocator(document),
+		experimentKeys: map[string]bool{},
+	}
+	c.convert(doc)
+	if len(c.errs) > 0 {
+		c.errs.sort()
+		return nil, c.errs
+	}
+	return c.builder.Build()
+}
+
+type converter struct {
+	builder        *datafilebuilder.Builder
+	locator        *locator
+	experimentKeys map[string]bool
+	errs           Errors
+}
+
+func (c *converter) errorf(p path, format string, args ...interface{}) {
+	c.errs = append(c.errs, &Error{Line: c.locator.line(p), Message: fmt.Sprintf(format, args...)})
+}
+
+func (c *converter) convert(doc Document) {
+	if doc.Revision != "" {
+		c.builder.Revision(doc.Revision)
+	}
+	c.builder.SDKKey(doc.SDKKey).SendFlagDecisions(doc.SendFlagDecisions)
+
+	audienceNames := make([]string, 0, len(doc.Audiences))
+	for name := range doc.Audiences {
+		audienceNames = append(audienceNames, name)
+	}
+	sort.Strings(audienceNames)
+	for _, name := range audienceNames {
+		c.convertAudience(path{"audiences", name}, name, doc.Audiences[name])
+	}
+
+	flagKeys := make([]string, 0, len(doc.Flags))
+	for key := range doc.Flags {
+		flagKeys = append(flagKeys, key)
+	}
+	sort.Strings(flagKeys)
+	for _, key := range flagKeys {
+		c.convertFlag(path{"flags", key}, key, doc.Flags[key])
+	}
+}
+
+func (c *converter) convertAudience(p path, name string, audience Audience) {
+	operator := "and"
+	switch audience.Match {
+	case "", "all":
+	case "any":
+		operator = "or"
+	default:
+		c.errorf(p.child("match"), `unknown match %q of audience %q, expected "all" or "any"`, audience.Match, name)
+	}
+	if len(audience.Conditions) == 0 {
+		c.errorf(p, "audience %q has no conditions", name)
+	}
+
+	conditions := []interface{}{operator}
+	for i, condition := range audience.Conditions {
+		if condition.Attribute == "" {
+			c.errorf(p.child("conditions", i), "condition %d of audience %q has no attribute", i+1, name)
+			continue
+		}
+		match := condition.Match
+		if match == "" {
+			match = "exact"
+		}
+		leaf := map[string]interface{}{"type": "custom_attribute", "name": condition.Attribute, "match": match}
+		if condition.Value != nil {
+			leaf["value"] = jsonValue(condition.Value)
+		}
+		conditions = append(conditions, leaf)
+		if c.builder.AttributeID(condition.Attribute) == "" {
+			c.builder.Attribute(condition.Attribute)
+		}
+	}
+	c.builder.Audience(name, conditions)
+}
+
+func (c *converter) convertFlag(p path, key string, flag Flag) {
+	flagBuilder := c.builder.Flag(key)
+
+	variableKeys := make([]string, 0, len(flag.Variables))
+	for variableKey := range flag.Variables {
+		variableKeys = append(variableKeys, variableKey)
+	}
+	sort.Strings(variableKeys)
+	variableTypes := map[string]entities.VariableType{}
+	for _, variableKey := range variableKeys {
+		variable := flag.Variables[variableKey]
+		variableType := entities.VariableType(variable.Type)
+		switch variableType {
+		case entities.Boolean, entities.Integer, entities.Double, entities.String, entities.JSON:
+		default:
+			c.errorf(p.child("variables", variableKey, "type"), "unknown type %q of variable %q", variable.Type, variableKey)
+			continue
+		}
+		value := c.variableValue(p.child("variables", variableKey, "default"), variableKey, variableType, variable.Default)
+		flagBuilder.Variable(variableKey, variableType, value)
+		variableTypes[variableKey] = variableType
+	}
+
+	for i, experiment := range flag.Experiments {
+		c.convertExperiment(p.child("experiments", i), flagBuilder, variableTypes, experiment)
+	}
+
+	for i, rule := range flag.Rules {
+		rp := p.child("rules", i)
+		if rule.Key == "" {
+			rule.Key = fmt.Sprintf("%s_rule_%d", key, i+1)
+		}
+		if !c.addExperimentKey(rp.child("key"), rule.Key) {
+			continue
+		}
+
+		enabled := rule.Enabled == nil || *rule.Enabled
+		variationKey := "off"
+		if enabled {
+			variationKey = "on"
+		}
+		ruleBuilder := flagBuilder.Rule(rule.Key).Variation(variationKey, enabled)
+		c.variationVariables(rp.child("variables"), ruleBuilder, variableTypes, rule.Variables)
+		ruleBuilder.Audiences(c.audiences(rp.child("audiences"), rule.Audiences)...)
+
+		percentage := 100.0
+		if rule.Percentage != nil {
+			percentage = *rule.Percentage
+			if percentage < 0 || percentage > 100 {
+				c.errorf(rp.child("percentage"), "percentage %v of rule %q is not between 0 and 100", percentage, rule.Key)
+				continue
+			}
+		}
+		ruleBuilder.Traffic(percentage)
+	}
+}
+
+func (c *converter) convertExperiment(p path, flagBuilder *datafilebuilder.FlagBuilder, variableTypes map[string]entities.VariableType, experiment Experiment) {
+	if experiment.Key == "" {
+		c.errorf(p, "experiment has no key")
+		return
+	}
+	if !c.addExperimentKey(p.child("key"), experiment.Key) {
+		return
+	}
+
+	experimentBuilder := flagBuilder.Experiment(experiment.Key)
+	switch status := entities.ExperimentStatus(experiment.Status); status {
+	case "":
+	case entities.ExperimentStatusRunning, entities.ExperimentStatusLaunched, entities.ExperimentStatusPaused,
+		entities.ExperimentStatusArchived, entities.ExperimentStatusNotStarted:
+		experimentBuilder.Status(status)
+	default:
+		c.errorf(p.child("status"), "unknown status %q of experiment %q", experiment.Status, experiment.Key)
+	}
+	experimentBuilder.Audiences(c.audiences(p.child("audiences"), experiment.Audiences)...)
+
+	if len(experiment.Variations) == 0 {
+		c.errorf(p, "experiment %q has no variations", experiment.Key)
+		return
+	}
+	var percentages []float64
+	total := 0.0
+	variationKeys := map[string]bool{}
+	for i, variation := range experiment.Variations {
+		vp := p.child("variations", i)
+		if variation.Key == "" {
+			c.errorf(vp, "variation %d of experiment %q has no key", i+1, experiment.Key)
+			continue
+		}
+		if variationKeys[variation.Key] {
+			c.errorf(vp.child("key"), "duplicate variation %q of experiment %q", variation.Key, experiment.Key)
+			continue
+		}
+		variationKeys[variation.Key] = true
+		if (variation.Percentage != nil) != (experiment.Variations[0].Percentage != nil) {
+			c.errorf(vp, "either all or none of the variations of experiment %q must have a percentage", experiment.Key)
+		} else if variation.Percentage != nil {
+			if *variation.Percentage < 0 {
+				c.errorf(vp.child("percentage"), "negative percentage %v of variation %q", *variation.Percentage, variation.Key)
+			}
+			percentages = append(percentages, *variation.Percentage)
+			total += *variation.Percentage
+		}
+
+		experimentBuilder.Variation(variation.Key, variation.Enabled == nil || *variation.Enabled)
+		c.variationVariables(vp.child("variables"), experimentBuilder, variableTypes, variation.Variables)
+	}
+	if total > 100 {
+		c.errorf(p.child("variations"), "percentages of the variations of experiment %q add up to %v, more than 100", experiment.Key, total)
+	}
+	experimentBuilder.Traffic(percentages...)
+}
+
+func (c *converter) addExperimentKey(p path, key string) bool {
+	if c.experimentKeys[key] {
+		c.errorf(p, "duplicate experiment or rule key %q", key)
+		return false
+	}
+	c.experimentKeys[key] = true
+	return true
+}
+
+func (c *converter) audiences(p path, names []string) []string {
+	defined := []string{}
+	for i, name := range names {
+		if c.builder.AudienceID(name) == "" {
+			c.errorf(p.child(i), "audience %q is not defined", name)
+			continue
+		}
+		defined = append(defined, name)
+	}
+	return defined
+}
+
+func (c *converter) variationVariables(p path, experimentBuilder *datafilebuilder.ExperimentBuilder, variableTypes map[string]entities.VariableType, values map[string]interface{}) {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		variableType, ok := variableTypes[key]
+		if !ok {
+			c.errorf(p.child(key), "variable %q is not defined", key)
+			continue
+		}
+		experimentBuilder.VariableValue(key, c.variableValue(p.child(key), key, variableType, values[key]))
+	}
+}
+
+// variableValue formats a YAML value as the string value of a variable and checks it matches the variable type
+func (c *converter) variableValue(p path, key string, variableType entities.VariableType, value interface{}) string {
+	var formatted string
+	switch value := value.(type) {
+	case nil:
+	case string:
+		formatted = value
+	case bool, int, int64, uint64, float64:
+		formatted = fmt.Sprint(value)
+	default:
+		encoded, err := json.Marshal(jsonValue(value))
+		if err != nil {
+			c.errorf(p, "value of variable %q can not be encoded as JSON: %v", key, err)
+		}
+		formatted = string(encoded)
+	}
+
+	var err error
+	switch variableType {
+	case entities.Boolean:
+		_, err = strconv.ParseBool(formatted)
+	case entities.Integer:
+		_, err = strconv.ParseInt(formatted, 10, 64)
+	case entities.Double:
+		_, err = strconv.ParseFloat(formatted, 64)
+	case entities.JSON:
+		if !json.Valid([]byte(formatted)) {
+			err = fmt.Errorf("invalid JSON")
+		}
+	}
+	if err != nil {
+		c.errorf(p, "value %q of variable %q is not a valid %s", formatted, key, variableType)
+	}
+	return formatted
+}
+
+// jsonValue converts the maps decoded from YAML, which may have keys of any type, to maps with string keys
+func jsonValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(value))
+		for k, v := range value {
+			converted[fmt.Sprint(k)] = jsonValue(v)
+		}
+		return converted
+	case []interface{}:
+		converted := make([]interface{}, len(value))
+		for i, v := range value {
+			converted[i] = jsonValue(v)
+		}
+		return converted
+	default:
+		return value
+	}
+}
@@ -0,0 +1,190 @@
+/****************************************************************************
+ * Copyright 2026, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+package datafileyaml
+
+import (
+	"testing"
+
+	"github.com/WolffunService/experiment/pkg/config/datafileprojectconfig"
+	"github.com/WolffunService/experiment/pkg/entities"
+	"github.com/WolffunService/experiment/pkg/logging"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testDocument = `# flags of the checkout service
+revision: 3
+sdkKey: yaml_sdk_key
+audiences:
+  adults:
+    conditions:
+      - {attribute: age, match: ge, value: 18}
+      - attribute: country
+        value: fr
+  beta:
+    match: any
+    conditions:
+    - attribute: beta
+      value: true
+flags:
+  checkout:
+    variables:
+      color: {type: string, default: red}
+      retries: {type: integer, default: 3}
+      settings:
+        type: json
+        default:
+          layout: compact
+          columns: [1, 2]
+    experiments:
+      - key: checkout_test
+        audiences: [beta]
+        variations:
+          - {key: control, percentage: 50, enabled: false}
+          - key: treatment
+            percentage: 50
+            variables: {color: blue, retries: 5}
+    rules:
+      - key: adults
+        audiences: [adults]
+        percentage: 20
+      - enabled: false
+`
+
+func TestConvert(t *testing.T) {
+	datafile, err := Convert([]byte(testDocument))
+	assert.NoError(t, err)
+	assert.Empty(t, datafileprojectconfig.Validate(datafile))
+
+	projectConfig, err := datafileprojectconfig.NewDatafileProjectConfig(datafile, logging.GetLogger("", "DatafileProjectConfig"))
+	assert.NoError(t, err)
+	assert.Equal(t, "3", projectConfig.GetRevision())
+	assert.Equal(t, "yaml_sdk_key", projectConfig.GetSdkKey())
+	assert.NotEmpty(t, projectConfig.GetAttributeID("age"))
+	assert.NotEmpty(t, projectConfig.GetAttributeID("beta"))
+
+	settings, err := projectConfig.GetVariableByKey("checkout", "settings")
+	assert.NoError(t, err)
+	assert.Equal(t, entities.JSON, settings.Type)
+	assert.JSONEq(t, `{"layout": "compact", "columns": [1, 2]}`, settings.DefaultValue)
+
+	feature, err := projectConfig.GetFeatureByKey("checkout")
+	assert.NoError(t, err)
+	if assert.Len(t, feature.Rollout.Experiments, 2) {
+		rule := feature.Rollout.Experiments[0]
+		assert.Equal(t, "adults", rule.Key)
+		assert.Len(t, rule.AudienceIds, 1)
+		assert.Equal(t, 2000, rule.TrafficAllocation[0].EndOfRange)
+		everyoneElse := feature.Rollout.Experiments[1]
+		assert.Equal(t, "checkout_rule_2", everyoneElse.Key)
+		assert.Empty(t, everyoneElse.AudienceIds)
+		assert.Equal(t, 10000, everyoneElse.TrafficAllocation[0].EndOfRange)
+		assert.False(t, everyoneElse.Variations[everyoneElse.TrafficAllocation[0].EntityID].FeatureEnabled)
+	}
+
+	experiment, err := projectConfig.GetExperimentByKey("checkout_test")
+	assert.NoError(t, err)
+	assert.Equal(t, []int{5000, 10000}, []int{experiment.TrafficAllocation[0].EndOfRange, experiment.TrafficAllocation[1].EndOfRange})
+	treatment := experiment.Variations[experiment.VariationKeyToIDMap["treatment"]]
+	assert.True(t, treatment.FeatureEnabled)
+	color, _ := projectConfig.GetVariableByKey("checkout", "color")
+	assert.Equal(t, "blue", treatment.Variables[color.ID].Value)
+	assert.False(t, experiment.Variations[experiment.VariationKeyToIDMap["control"]].FeatureEnabled)
+
+	audience, err := projectConfig.GetAudienceByID(experiment.AudienceIds[0])
+	assert.NoError(t, err)
+	assert.Equal(t, "beta", audience.Name)
+	assert.Equal(t, "or", audience.ConditionTree.Operator)
+
+	again, err := Convert([]byte(testDocument))
+	assert.NoError(t, err)
+	assert.Equal(t, string(datafile), string(again))
+}
+
+func TestConvertReportsLineNumbers(t *testing.T) {
+	document := `audiences:
+  adults:
+    match: every
+    conditions:
+      - {match: ge, value: 18}
+flags:
+  checkout:
+    variables:
+      retries: {type: integer, default: many}
+      size: {type: number}
+    experiments:
+      - key: test
+        status: Stopped
+        variations:
+          - {key: a, percentage: 80}
+          - key: b
+            percentage: 30
+            variables:
+              color: blue
+      - key: empty
+    rules:
+      - key: test
+      - audiences: [adults, kids]
+        percentage: 120
+`
+	_, err := Convert([]byte(document))
+	assert.Equal(t, Errors{
+		{Line: 3, Message: `unknown match "every" of audience "adults", expected "all" or "any"`},
+		{Line: 5, Message: `condition 1 of audience "adults" has no attribute`},
+		{Line: 9, Message: `value "many" of variable "retries" is not a valid integer`},
+		{Line: 10, Message: `unknown type "number" of variable "size"`},
+		{Line: 13, Message: `unknown status "Stopped" of experiment "test"`},
+		{Line: 14, Message: `percentages of the variations of experiment "test" add up to 110, more than 100`},
+		{Line: 19, Message: `variable "color" is not defined`},
+		{Line: 20, Message: `experiment "empty" has no variations`},
+		{Line: 22, Message: `duplicate experiment or rule key "test"`},
+		{Line: 23, Message: `audience "kids" is not defined`},
+		{Line: 24, Message: `percentage 120 of rule "checkout_rule_2" is not between 0 and 100`},
+	}, err)
+}
+
+func TestConvertReportsDecoderErrors(t *testing.T) {
+	_, err := Convert([]byte("flags:\n  checkout:\n    rule: []\n"))
+	assert.Equal(t, Errors{{Line: 3, Message: "field rule not found in type datafileyaml.Flag"}}, err)
+	assert.EqualError(t, err, "line 3: field rule not found in type datafileyaml.Flag")
+
+	_, err = Convert([]byte("flags:\n  checkout: [\n"))
+	if assert.IsType(t, Errors{}, err) {
+		assert.NotZero(t, err.(Errors)[0].Line)
+	}
+}
+
+func TestLocator(t *testing.T) {
+	l := newLocator([]byte(`a:
+  b: 1
+  "c":
+  - x: 1
+  -
+    x: 2
+    y:
+      - 3
+d: {e: 1}
+`))
+	assert.Equal(t, 2, l.line(path{"a", "b"}))
+	assert.Equal(t, 3, l.line(path{"a", "c"}))
+	assert.Equal(t, 4, l.line(path{"a", "c", 0, "x"}))
+	assert.Equal(t, 6, l.line(path{"a", "c", 1, "x"}))
+	assert.Equal(t, 8, l.line(path{"a", "c", 1, "y", 0}))
+	assert.Equal(t, 9, l.line(path{"d", "e"}))
+	assert.Equal(t, 1, l.line(path{"a", "missing"}))
+	assert.Equal(t, 0, l.line(path{"missing"}))
+}
@@ -0,0 +1,203 @@
+/****************************************************************************
+ * Copyright 2026, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+package datafileyaml
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+var yamlErrorLine = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+
+// Error is an error found at a line of the YAML document, the line is 0 when it is not known
+type Error struct {
+	Line    int
+	Message string
+}
+
+func (e *Error) Error() string {
+	if e.Line == 0 {
+		return e.Message
+	}
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+// Errors lists all the errors found in a YAML document, sorted by line
+type Errors []*Error
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+func (e Errors) sort() {
+	sort.SliceStable(e, func(i, j int) bool {
+		return e[i].Line < e[j].Line
+	})
+}
+
+// yamlErrors converts the syntax and type errors of the YAML decoder, which carry the line in their message
+func yamlErrors(err error) Errors {
+	messages := []string{err.Error()}
+	if typeError, ok := err.(*yaml.TypeError); ok {
+		messages = typeError.Errors
+	}
+
+	errs := Errors{}
+	for _, message := range messages {
+		if match := yamlErrorLine.FindStringSubmatch(message); match != nil {
+			line, _ := strconv.Atoi(match[1])
+			errs = append(errs, &Error{Line: line, Message: match[2]})
+			continue
+		}
+		errs = append(errs, &Error{Message: message})
+	}
+	errs.sort()
+	return errs
+}
+
+// path is the location of a value in the YAML document, made of mapping keys and sequence indexes
+type path []interface{}
+
+func (p path) child(elements ...interface{}) path {
+	return append(append(path{}, p...), elements...)
+}
+
+// locator finds the line of a path in a block style YAML document. The YAML decoder does not report the position of
+// the values it decodes, so the lines are found from the indentation of the mapping keys and sequence entries.
+type locator struct {
+	lines []sourceLine
+}
+
+type sourceLine struct {
+	number int
+	indent int
+	text   string
+}
+
+func newLocator(document []byte) *locator {
+	l := &locator{}
+	for i, line := range strings.Split(string(document), "\n") {
+		text := strings.TrimLeft(line, " ")
+		if trimmed := strings.TrimSpace(text); trimmed == "" || strings.HasPrefix(trimmed, "#") || trimmed == "---" {
+			continue
+		}
+		l.lines = append(l.lines, sourceLine{number: i + 1, indent: len(line) - len(text), text: strings.TrimRight(text, " \r")})
+	}
+	return l
+}
+
+// line returns the line of the deepest element of the path that was found, or 0 if none was
+func (l *locator) line(p path) int {
+	block := l.lines
+	number := 0
+	for _, element := range p {
+		if len(block) == 0 {
+			break
+		}
+		var value []sourceLine
+		var line int
+		var found bool
+		switch element := element.(type) {
+		case string:
+			value, line, found = findKey(block, element)
+		case int:
+			value, line, found = findEntry(block, element)
+		}
+		if !found {
+			break
+		}
+		block, number = value, line
+	}
+	return number
+}
+
+// findKey returns the block of the value of a mapping key and the line of the key
+func findKey(block []sourceLine, key string) ([]sourceLine, int, bool) {
+	indent := block[0].indent
+	for i, line := range block {
+		if line.indent < indent {
+			break
+		}
+		if line.indent == indent && isKey(line.text, key) {
+			value := block[i+1:]
+			end := len(nested(value, indent))
+			// a sequence may be indented as deep as its key
+			for end < len(value) && value[end].indent == indent && isEntry(value[end].text) {
+				end += 1 + len(nested(value[end+1:], indent))
+			}
+			return value[:end], line.number, true
+		}
+	}
+	return nil, 0, false
+}
+
+// findEntry returns the block of the sequence entry at the given index and the line it starts at
+func findEntry(block []sourceLine, index int) ([]sourceLine, int, bool) {
+	indent := block[0].indent
+	for i, line := range block {
+		if line.indent < indent {
+			break
+		}
+		if line.indent != indent || !isEntry(line.text) {
+			continue
+		}
+		if index > 0 {
+			index--
+			continue
+		}
+		entry := nested(block[i+1:], indent)
+		if content := strings.TrimLeft(strings.TrimPrefix(line.text, "-"), " "); content != "" {
+			// the first line of the entry continues after the dash, at the indentation of the following lines
+			first := sourceLine{number: line.number, indent: indent + len(line.text) - len(content), text: content}
+			entry = append([]sourceLine{first}, entry...)
+		}
+		return entry, line.number, true
+	}
+	return nil, 0, false
+}
+
+// nested returns the leading lines of the block which are indented deeper than the given indentation
+func nested(block []sourceLine, indent int) []sourceLine {
+	for i, line := range block {
+		if line.indent <= indent {
+			return block[:i]
+		}
+	}
+	return block
+}
+
+func isEntry(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+func isKey(text, key string) bool {
+	for _, quoted := range []string{key, `"` + key + `"`, "'" + key + "'"} {
+		if strings.HasPrefix(text, quoted) && strings.HasPrefix(strings.TrimLeft(text[len(quoted):], " "), ":") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,330 @@
+/****************************************************************************
+ * Copyright 2026, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package config //
+package config
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/WolffunService/experiment/pkg/config/datafileprojectconfig"
+	"github.com/WolffunService/experiment/pkg/config/datafileyaml"
+	"github.com/WolffunService/experiment/pkg/logging"
+	"github.com/WolffunService/experiment/pkg/metrics"
+	"github.com/WolffunService/experiment/pkg/notification"
+	"github.com/WolffunService/experiment/pkg/registry"
+)
+
+// DefaultFileCheckInterval sets default interval for checking the datafile on disk
+const DefaultFileCheckInterval = 10 * time.Second
+
+// FileProjectConfigManager maintains a dynamic copy of the project config by watching a datafile on the local disk.
+// The file is checked at a given (configurable) interval and re-read whenever its modification time or size changes.
+// Files with a .yaml or .yml extension are converted from the YAML authoring format of the datafileyaml package.
+type FileProjectConfigManager struct {
+	path               string
+	checkInterval      time.Duration
+	notificationCenter notification.Center
+	sdkKey             string
+	logger             logging.OptimizelyLogProducer
+	verifier           DatafileVerifier
+	metricsRegistry    metrics.Registry
+
+	modTime    time.Time
+	size       int64
+	sigModTime time.Time
+	rejected   bool
+
+	configLock       sync.RWMutex
+	err              error
+	projectConfig    ProjectConfig
+	optimizelyConfig *OptimizelyConfig
+	ready            chan struct{}
+	readyOnce        sync.Once
+}
+
+// FileOptionFunc is used to provide custom configuration to the FileProjectConfigManager.
+type FileOptionFunc func(*FileProjectConfigManager)
+
+// WithFileCheckInterval is an optional function, sets a passed interval for checking the datafile on disk
+func WithFileCheckInterval(interval time.Duration) FileOptionFunc {
+	return func(f *FileProjectConfigManager) {
+		f.checkInterval = interval
+	}
+}
+
+// WithFileDatafileVerifier is an optional function, sets a verifier of the datafile signature which is read from the
+// sidecar file at the datafile path with the DatafileSignatureSuffix
+func WithFileDatafileVerifier(verifier DatafileVerifier) FileOptionFunc {
+	return func(f *FileProjectConfigManager) {
+		f.verifier = verifier
+	}
+}
+
+// WithFileMetricsRegistry is an optional function, sets a registry the compressed and decompressed datafile sizes are reported to
+func WithFileMetricsRegistry(metricsRegistry metrics.Registry) FileOptionFunc {
+	return func(f *FileProjectConfigManager) {
+		f.metricsRegistry = metricsRegistry
+	}
+}
+
+// WithFileNotificationCenter is an optional function, sets the notification center config updates are sent to,
+// instead of the one the registry holds for the SDK key
+func WithFileNotificationCenter(notificationCenter notification.Center) FileOptionFunc {
+	return func(f *FileProjectConfigManager) {
+		f.notificationCenter = notificationCenter
+	}
+}
+
+// NewFileProjectConfigManager returns an instance of the file config manager which loads the datafile from the given path
+func NewFileProjectConfigManager(sdkKey, path string, fileManagerOptions ...FileOptionFunc) *FileProjectConfigManager {
+	fileProjectConfigManager := &FileProjectConfigManager{
+		path:               path,
+		checkInterval:      DefaultFileCheckInterval,
+		notificationCenter: registry.GetNotificationCenter(sdkKey),
+		metricsRegistry:    metrics.NewNoopRegistry(),
+		ready:              make(chan struct{}),
+		sdkKey:             sdkKey,
+		logger:             logging.GetLogger(sdkKey, "FileProjectConfigManager"),
+	}
+
+	for _, opt := range fileManagerOptions {
+		opt(fileProjectConfigManager)
+	}
+
+	fileProjectConfigManager.SyncConfig() // initial load
+	return fileProjectConfigManager
+}
+
+// SyncConfig re-reads the datafile if it changed on disk and updates projectConfig
+func (cm *FileProjectConfigManager) SyncConfig() {
+	cm.configLock.Lock()
+	previousConfig := cm.projectConfig
+	updated, rejection := cm.syncConfig()
+	projectConfig := cm.projectConfig
+	cm.configLock.Unlock()
+
+	if rejection != nil {
+		cm.sendDatafileRejectedNotification(rejection)
+	}
+	if updated {
+		cm.sendConfigUpdateNotification(previousConfig, projectConfig)
+	}
+}
+
+// syncConfig must be called with the config lock held, it returns true if a new revision was set and the reason
+// the datafile was rejected, if it was
+func (cm *FileProjectConfigManager) syncConfig() (updated bool, rejection error) {
+	info, err := os.Stat(cm.path)
+	if err != nil {
+		cm.logger.Warning(fmt.Sprintf("unable to stat datafile %s", cm.path))
+		cm.err = fmt.Errorf("unable to read datafile from %s: %v", cm.path, err)
+		return false, nil
+	}
+	var sigModTime time.Time
+	if cm.verifier != nil {
+		if sigInfo, err := os.Stat(cm.path + DatafileSignatureSuffix); err == nil {
+			sigModTime = sigInfo.ModTime()
+		}
+	}
+
+	if (cm.projectConfig != nil || cm.rejected) && info.ModTime().Equal(cm.modTime) && info.Size() == cm.size && sigModTime.Equal(cm.sigModTime) {
+		return false, nil
+	}
+
+	datafile, err := ioutil.ReadFile(cm.path)
+	if err != nil {
+		cm.logger.Warning(fmt.Sprintf("unable to read datafile %s", cm.path))
+		cm.err = fmt.Errorf("unable to read datafile from %s: %v", cm.path, err)
+		return false, nil
+	}
+	if datafile, err = decompressDatafile(datafile, cm.metricsRegistry); err != nil {
+		// the file may be in the middle of being written, it will be retried on the next check
+		cm.logger.Warning(fmt.Sprintf("unable to decompress datafile %s", cm.path))
+		cm.err = err
+		return false, nil
+	}
+
+	if cm.verifier != nil {
+		if err := cm.verifySignature(datafile); err != nil {
+			// remember the rejected files so that the rejection is reported once per change
+			cm.logger.Error(fmt.Sprintf("Rejecting datafile %s", cm.path), err)
+			cm.modTime, cm.size, cm.sigModTime = info.ModTime(), info.Size(), sigModTime
+			cm.rejected = true
+			cm.err = err
+			return false, err
+		}
+	}
+
+	if isYAMLDatafilePath(cm.path) {
+		if datafile, err = datafileyaml.Convert(datafile); err != nil {
+			cm.logger.Error(fmt.Sprintf("Rejecting YAML datafile %s", cm.path), err)
+			cm.modTime, cm.size, cm.sigModTime = info.ModTime(), info.Size(), sigModTime
+			cm.rejected = true
+			cm.err = err
+			return false, err
+		}
+	}
+
+	projectConfig, err := datafileprojectconfig.NewDatafileProjectConfig(datafile, logging.GetLogger(cm.sdkKey, "NewDatafileProjectConfig"))
+	if err != nil {
+		// the file may be in the middle of being written, it will be retried on the next check
+		cm.logger.Warning("failed to create project config")
+		cm.err = ErrDatafileParse
+		return false, nil
+	}
+	cm.modTime = info.ModTime()
+	cm.size = info.Size()
+	cm.sigModTime = sigModTime
+	cm.rejected = false
+	cm.err = nil
+
+	var previousRevision string
+	if cm.projectConfig != nil {
+		previousRevision = cm.projectConfig.GetRevision()
+	}
+	if projectConfig.GetRevision() == previousRevision {
+		cm.logger.Debug(fmt.Sprintf("No datafile updates. Current revision number: %s", previousRevision))
+		return false, nil
+	}
+
+	cm.projectConfig = projectConfig
+	if cm.optimizelyConfig != nil {
+		cm.optimizelyConfig = NewOptimizelyConfig(projectConfig)
+	}
+	cm.readyOnce.Do(func() { close(cm.ready) })
+	cm.logger.Debug(fmt.Sprintf("New datafile set with revision: %s. Old revision: %s", projectConfig.GetRevision(), previousRevision))
+	return true, nil
+}
+
+// isYAMLDatafilePath returns true if the path has a YAML extension, optionally followed by a gzip one
+func isYAMLDatafilePath(path string) bool {
+	switch filepath.Ext(strings.TrimSuffix(path, ".gz")) {
+	case ".yaml", ".yml":
+		return true
+	}
+	return false
+}
+
+// verifySignature checks the sidecar signature file of the datafile
+func (cm *FileProjectConfigManager) verifySignature(datafile []byte) error {
+	signature, err := ioutil.ReadFile(cm.path + DatafileSignatureSuffix)
+	if err != nil {
+		return ErrMissingSignature
+	}
+	return cm.verifier.Verify(datafile, signature)
+}
+
+// Start starts watching the datafile
+func (cm *FileProjectConfigManager) Start(ctx context.Context) {
+	if cm.checkInterval <= 0 {
+		cm.logger.Info("File Config Manager Disabled")
+		return
+	}
+	cm.logger.Debug("File Config Manager Initiated")
+	t := time.NewTicker(cm.checkInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			cm.SyncConfig()
+		case <-ctx.Done():
+			cm.logger.Debug("File Config Manager Stopped")
+			return
+		}
+	}
+}
+
+// GetConfig returns the project config
+func (cm *FileProjectConfigManager) GetConfig() (ProjectConfig, error) {
+	cm.configLock.RLock()
+	defer cm.configLock.RUnlock()
+	if cm.projectConfig == nil {
+		return cm.projectConfig, cm.err
+	}
+	return cm.projectConfig, nil
+}
+
+// Ready returns a channel which is closed once the first valid config is set
+func (cm *FileProjectConfigManager) Ready() <-chan struct{} {
+	return cm.ready
+}
+
+// GetOptimizelyConfig returns the optimizely project config
+func (cm *FileProjectConfigManager) GetOptimizelyConfig() *OptimizelyConfig {
+	cm.configLock.Lock()
+	defer cm.configLock.Unlock()
+	if cm.optimizelyConfig != nil {
+		return cm.optimizelyConfig
+	}
+	cm.optimizelyConfig = NewOptimizelyConfig(cm.projectConfig)
+	return cm.optimizelyConfig
+}
+
+// OnProjectConfigUpdate registers a handler for ProjectConfigUpdate notifications
+func (cm *FileProjectConfigManager) OnProjectConfigUpdate(callback func(notification.ProjectConfigUpdateNotification)) (int, error) {
+	handler := func(payload interface{}) {
+		if projectConfigUpdateNotification, ok := payload.(notification.ProjectConfigUpdateNotification); ok {
+			callback(projectConfigUpdateNotification)
+		} else {
+			cm.logger.Warning(fmt.Sprintf("Unable to convert notification payload %v into ProjectConfigUpdateNotification", payload))
+		}
+	}
+	id, err := cm.notificationCenter.AddHandler(notification.ProjectConfigUpdate, handler)
+	if err != nil {
+		cm.logger.Warning("Problem with adding notification handler")
+		return 0, err
+	}
+	return id, nil
+}
+
+// RemoveOnProjectConfigUpdate removes handler for ProjectConfigUpdate notification with given id
+func (cm *FileProjectConfigManager) RemoveOnProjectConfigUpdate(id int) error {
+	if err := cm.notificationCenter.RemoveHandler(id, notification.ProjectConfigUpdate); err != nil {
+		cm.logger.Warning("Problem with removing notification handler")
+		return err
+	}
+	return nil
+}
+
+func (cm *FileProjectConfigManager) sendDatafileRejectedNotification(reason error) {
+	if cm.notificationCenter != nil {
+		datafileRejectedNotification := notification.DatafileRejectedNotification{
+			Type:   notification.DatafileRejected,
+			Source: cm.path,
+			Reason: reason.Error(),
+		}
+		if err := cm.notificationCenter.Send(notification.DatafileRejected, datafileRejectedNotification); err != nil {
+			cm.logger.Warning("Problem with sending notification")
+		}
+	}
+}
+
+func (cm *FileProjectConfigManager) sendConfigUpdateNotification(oldConfig, newConfig ProjectConfig) {
+	if cm.notificationCenter != nil {
+		projectConfigUpdateNotification := newProjectConfigUpdateNotification(oldConfig, newConfig)
+		if err := cm.notificationCenter.Send(notification.ProjectConfigUpdate, projectConfigUpdateNotification); err != nil {
+			cm.logger.Warning("Problem with sending notification")
+		}
+	}
+}
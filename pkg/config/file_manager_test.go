@@ -0,0 +1,152 @@
+/****************************************************************************
+ * Copyright 2026, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package config //
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/WolffunService/experiment/pkg/notification"
+	"github.com/WolffunService/experiment/pkg/registry"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func tempDatafilePath(t *testing.T) (path string, cleanup func()) {
+	dir, err := ioutil.TempDir("", "file_manager_test")
+	assert.NoError(t, err)
+	return filepath.Join(dir, "datafile.json"), func() { os.RemoveAll(dir) }
+}
+
+func writeDatafile(t *testing.T, path, datafile string, modTime time.Time) {
+	assert.NoError(t, ioutil.WriteFile(path, []byte(datafile), 0600))
+	assert.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+func TestNewFileProjectConfigManager(t *testing.T) {
+	path, cleanup := tempDatafilePath(t)
+	defer cleanup()
+	writeDatafile(t, path, `{"revision":"42","version":"4"}`, time.Now())
+
+	configManager := NewFileProjectConfigManager("file_sdk_key", path, WithFileCheckInterval(time.Minute))
+	assert.Equal(t, time.Minute, configManager.checkInterval)
+
+	actual, err := configManager.GetConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "42", actual.GetRevision())
+	assert.Equal(t, "42", configManager.GetOptimizelyConfig().Revision)
+}
+
+func TestNewFileProjectConfigManagerMissingFile(t *testing.T) {
+	path, cleanup := tempDatafilePath(t)
+	defer cleanup()
+	configManager := NewFileProjectConfigManager("file_sdk_key", path)
+
+	actual, err := configManager.GetConfig()
+	assert.Nil(t, actual)
+	assert.Error(t, err)
+}
+
+func TestFileProjectConfigManagerSyncConfigOnChange(t *testing.T) {
+	path, cleanup := tempDatafilePath(t)
+	defer cleanup()
+	modTime := time.Now().Add(-time.Hour)
+	writeDatafile(t, path, `{"revision":"42","version":"4"}`, modTime)
+
+	sdkKey := "file_sync_sdk_key"
+	configManager := NewFileProjectConfigManager(sdkKey, path)
+	revisions := []string{}
+	_, err := configManager.OnProjectConfigUpdate(func(n notification.ProjectConfigUpdateNotification) {
+		revisions = append(revisions, n.Revision)
+	})
+	assert.NoError(t, err)
+
+	// unchanged file is not re-read
+	configManager.SyncConfig()
+	assert.Empty(t, revisions)
+
+	// a partially written file keeps the previous config
+	writeDatafile(t, path, `{"revision":"43",`, modTime.Add(time.Minute))
+	configManager.SyncConfig()
+	actual, err := configManager.GetConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "42", actual.GetRevision())
+	assert.Empty(t, revisions)
+
+	writeDatafile(t, path, `{"revision":"43","version":"4"}`, modTime.Add(2*time.Minute))
+	configManager.SyncConfig()
+	actual, err = configManager.GetConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "43", actual.GetRevision())
+	assert.Equal(t, []string{"43"}, revisions)
+}
+
+func TestFileProjectConfigManagerConvertsYAML(t *testing.T) {
+	dir, err := ioutil.TempDir("", "file_manager_test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "flags.yaml")
+	modTime := time.Now().Add(-time.Hour)
+	writeDatafile(t, path, "revision: 42\nflags:\n  checkout:\n    rules:\n      - percentage: 50\n", modTime)
+
+	var rejections []notification.DatafileRejectedNotification
+	_, err = registry.GetNotificationCenter("file_yaml_sdk_key").AddHandler(notification.DatafileRejected, func(payload interface{}) {
+		rejections = append(rejections, payload.(notification.DatafileRejectedNotification))
+	})
+	assert.NoError(t, err)
+
+	configManager := NewFileProjectConfigManager("file_yaml_sdk_key", path)
+	assert.Equal(t, "42", currentRevision(configManager))
+	actual, _ := configManager.GetConfig()
+	feature, err := actual.GetFeatureByKey("checkout")
+	assert.NoError(t, err)
+	assert.Equal(t, 5000, feature.Rollout.Experiments[0].TrafficAllocation[0].EndOfRange)
+
+	// an invalid document is rejected once with its line numbered errors and the previous config is kept
+	writeDatafile(t, path, "revision: 43\nflags:\n  checkout:\n    rules:\n      - percentage: 150\n", modTime.Add(time.Minute))
+	configManager.SyncConfig()
+	configManager.SyncConfig()
+	assert.Equal(t, "42", currentRevision(configManager))
+	if assert.Len(t, rejections, 1) {
+		assert.Equal(t, `line 5: percentage 150 of rule "checkout_rule_1" is not between 0 and 100`, rejections[0].Reason)
+	}
+
+	writeDatafile(t, path, "revision: 43\n", modTime.Add(2*time.Minute))
+	configManager.SyncConfig()
+	assert.Equal(t, "43", currentRevision(configManager))
+}
+
+func TestFileProjectConfigManagerStart(t *testing.T) {
+	path, cleanup := tempDatafilePath(t)
+	defer cleanup()
+	writeDatafile(t, path, `{"revision":"42","version":"4"}`, time.Now().Add(-time.Hour))
+
+	eg := newExecGroup()
+	configManager := NewFileProjectConfigManager("file_start_sdk_key", path, WithFileCheckInterval(50*time.Millisecond))
+	eg.Go(configManager.Start)
+
+	writeDatafile(t, path, `{"revision":"43","version":"4"}`, time.Now())
+	assertPeriodically(t, func() bool {
+		actual, _ := configManager.GetConfig()
+		return actual.GetRevision() == "43"
+	})
+	eg.TerminateAndWait()
+}
@@ -40,8 +40,10 @@ type ProjectConfig interface {
 	GetVariableByKey(featureKey string, variableKey string) (entities.Variable, error)
 	GetExperimentList() []entities.Experiment
 	GetRolloutList() (rolloutList []entities.Rollout)
+	GetHoldoutList() []entities.Experiment
 	GetFeatureList() []entities.Feature
 	GetGroupByID(string) (entities.Group, error)
+	GetLayerByID(string) (entities.Layer, error)
 	GetProjectID() string
 	GetRevision() string
 	SendFlagDecisions() bool
@@ -51,6 +53,12 @@ type ProjectConfig interface {
 	GetFlagVariationsMap() map[string][]entities.Variation
 }
 
+// ReadyNotifier is implemented by the ProjectConfigManagers which signal when their first valid config is set
+type ReadyNotifier interface {
+	// Ready returns a channel which is closed once the first valid config is set
+	Ready() <-chan struct{}
+}
+
 // ProjectConfigManager maintains an instance of the ProjectConfig
 type ProjectConfigManager interface {
 	GetConfig() (ProjectConfig, error)
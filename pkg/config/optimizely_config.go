@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/WolffunService/experiment/pkg/config/datafileprojectconfig/mappers"
 	"github.com/WolffunService/experiment/pkg/entities"
@@ -42,7 +43,9 @@ type OptimizelyConfig struct {
 	Attributes  []OptimizelyAttribute        `json:"attributes"`
 	Audiences   []OptimizelyAudience         `json:"audiences"`
 	Events      []OptimizelyEvent            `json:"events"`
-	datafile    string
+	// Overrides describes the local overrides applied on top of the datafile, see OverlayProjectConfigManager
+	Overrides []string `json:"overrides,omitempty"`
+	datafile  string
 }
 
 // GetDatafile returns a string representation of the environment's datafile
@@ -56,6 +59,23 @@ type OptimizelyExperiment struct {
 	Key           string                         `json:"key"`
 	Audiences     string                         `json:"audiences"`
 	VariationsMap map[string]OptimizelyVariation `json:"variationsMap"`
+	RampSchedule  []OptimizelyRampStep           `json:"rampSchedule,omitempty"`
+}
+
+// TrafficPercentage returns the percentage of traffic the ramp schedule of the experiment lets in at the given time,
+// which is 100 for experiments without a schedule
+func (e OptimizelyExperiment) TrafficPercentage(at time.Time) float64 {
+	experiment := entities.Experiment{RampSchedule: make([]entities.RampStep, len(e.RampSchedule))}
+	for i, step := range e.RampSchedule {
+		experiment.RampSchedule[i] = entities.RampStep(step)
+	}
+	return experiment.RampPercentage(at)
+}
+
+// OptimizelyRampStep has the info of a step of an experiment's ramp schedule
+type OptimizelyRampStep struct {
+	StartTime  time.Time `json:"startTime"`
+	Percentage float64   `json:"percentage"`
 }
 
 // OptimizelyAttribute has attribute info
@@ -265,11 +285,23 @@ func getDeliveryRules(variableByIDMap map[string]entities.Variable, audiencesByI
 			Key:           experiment.Key,
 			Audiences:     getExperimentAudiences(experiment, audiencesByID),
 			VariationsMap: getVariationsMap(feature, experiment.Variations, variableByIDMap),
+			RampSchedule:  getRampSchedule(experiment),
 		})
 	}
 	return optimizelyExpriments
 }
 
+func getRampSchedule(experiment entities.Experiment) []OptimizelyRampStep {
+	if len(experiment.RampSchedule) == 0 {
+		return nil
+	}
+	rampSchedule := make([]OptimizelyRampStep, len(experiment.RampSchedule))
+	for i, step := range experiment.RampSchedule {
+		rampSchedule[i] = OptimizelyRampStep(step)
+	}
+	return rampSchedule
+}
+
 func getRolloutExperimentsIdsMap(rolloutIDMap map[string]entities.Rollout) map[string]bool {
 	var rolloutExperimentIdsMap = map[string]bool{}
 	for _, rollout := range rolloutIDMap {
@@ -316,6 +348,7 @@ func getMappedExperiments(audiencesByID map[string]entities.Audience, experiment
 			Key:           experiment.Key,
 			Audiences:     getExperimentAudiences(experiment, audiencesByID),
 			VariationsMap: variationsMap,
+			RampSchedule:  getRampSchedule(experiment),
 		}
 	}
 	return mappedExperiments
@@ -21,6 +21,7 @@ import (
 	"io/ioutil"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
 )
@@ -182,6 +183,28 @@ func (s *OptimizelyConfigTestSuite) TestOptlyConfigGetDatafile() {
 	s.Equal(string(datafile), optimizelyConfig.GetDatafile())
 }
 
+func (s *OptimizelyConfigTestSuite) TestOptlyConfigRampSchedule() {
+	datafile := []byte(`{"version":"4", "experiments": [{"id": "11111", "key": "exp_1", "rampSchedule": [
+		{"startTime": "2026-03-02T00:00:00Z", "percentage": 5}, {"startTime": "2026-03-01T00:00:00Z", "percentage": 1}
+	]}, {"id": "11112", "key": "exp_2"}]}`)
+	projectMgr := NewStaticProjectConfigManagerWithOptions("", WithInitialDatafile(datafile))
+	optimizelyConfig := NewOptimizelyConfig(projectMgr.projectConfig)
+
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	experiment := optimizelyConfig.ExperimentsMap["exp_1"]
+	s.Equal([]OptimizelyRampStep{
+		{StartTime: start, Percentage: 1},
+		{StartTime: start.Add(24 * time.Hour), Percentage: 5},
+	}, experiment.RampSchedule)
+	s.Equal(0.0, experiment.TrafficPercentage(start.Add(-time.Hour)))
+	s.Equal(1.0, experiment.TrafficPercentage(start.Add(time.Hour)))
+	s.Equal(5.0, experiment.TrafficPercentage(start.Add(48*time.Hour)))
+
+	experiment = optimizelyConfig.ExperimentsMap["exp_2"]
+	s.Nil(experiment.RampSchedule)
+	s.Equal(100.0, experiment.TrafficPercentage(start))
+}
+
 func TestOptimizelyConfigTestSuite(t *testing.T) {
 	suite.Run(t, new(OptimizelyConfigTestSuite))
 }
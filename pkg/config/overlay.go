@@ -0,0 +1,208 @@
+/****************************************************************************
+ * Copyright 2026, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package config //
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+)
+
+// fullTrafficAllocation is the end of range of a traffic allocation serving everyone
+const fullTrafficAllocation = 10000
+
+// ConfigOverlay is a local override document layered on top of the remote datafile
+type ConfigOverlay struct {
+	Flags       map[string]FlagOverride       `json:"flags"`
+	Experiments map[string]ExperimentOverride `json:"experiments"`
+}
+
+// FlagOverride overrides the rollout and the variable defaults of a flag
+type FlagOverride struct {
+	// Enabled forces the rollout of the flag to 100%, dropping any ramp schedule, when true or to 0% when false.
+	// Experiments on the flag are still evaluated first unless they are disabled, and users in a global holdout still
+	// get the flag defaults rather than the forced rollout
+	Enabled *bool `json:"enabled,omitempty"`
+	// Variables replaces the default values of the variables by variable key
+	Variables map[string]string `json:"variables,omitempty"`
+}
+
+// ExperimentOverride overrides an experiment
+type ExperimentOverride struct {
+	// Disabled pauses the experiment so that no user is bucketed into it
+	Disabled bool `json:"disabled"`
+}
+
+// ParseConfigOverlay parses a JSON override document
+func ParseConfigOverlay(document []byte) (*ConfigOverlay, error) {
+	overlay := &ConfigOverlay{}
+	if err := json.Unmarshal(document, overlay); err != nil {
+		return nil, fmt.Errorf("unable to parse config overlay: %v", err)
+	}
+	return overlay, nil
+}
+
+// LoadConfigOverlay reads a JSON override document from the given path
+func LoadConfigOverlay(path string) (*ConfigOverlay, error) {
+	document, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read config overlay: %v", err)
+	}
+	return ParseConfigOverlay(document)
+}
+
+// Apply returns the datafile with the overrides applied along with a description of each applied override and
+// warnings about the overrides which do not match anything in the datafile
+func (o *ConfigOverlay) Apply(datafile []byte) (merged []byte, applied, warnings []string, err error) {
+	decoder := json.NewDecoder(bytes.NewReader(datafile))
+	decoder.UseNumber() // keeps numbers as they are in the datafile
+	var document map[string]interface{}
+	if err = decoder.Decode(&document); err != nil {
+		return nil, nil, nil, fmt.Errorf("unable to parse datafile: %v", err)
+	}
+
+	flags := objectsByField(document["featureFlags"], "key")
+	rollouts := objectsByField(document["rollouts"], "id")
+	experiments := objectsByField(document["experiments"], "key")
+	for _, group := range objects(document["groups"]) {
+		for key, experiment := range objectsByField(group["experiments"], "key") {
+			experiments[key] = experiment
+		}
+	}
+
+	flagKeys := make([]string, 0, len(o.Flags))
+	for flagKey := range o.Flags {
+		flagKeys = append(flagKeys, flagKey)
+	}
+	sort.Strings(flagKeys)
+	for _, flagKey := range flagKeys {
+		override := o.Flags[flagKey]
+		flag, ok := flags[flagKey]
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("flag %q not found", flagKey))
+			continue
+		}
+
+		variables := objectsByField(flag["variables"], "key")
+		variableKeys := make([]string, 0, len(override.Variables))
+		for variableKey := range override.Variables {
+			variableKeys = append(variableKeys, variableKey)
+		}
+		sort.Strings(variableKeys)
+		for _, variableKey := range variableKeys {
+			variable, ok := variables[variableKey]
+			if !ok {
+				warnings = append(warnings, fmt.Sprintf("variable %q of flag %q not found", variableKey, flagKey))
+				continue
+			}
+			variable["defaultValue"] = override.Variables[variableKey]
+			applied = append(applied, fmt.Sprintf("flag %q variable %q default set to %q", flagKey, variableKey, override.Variables[variableKey]))
+		}
+
+		if override.Enabled == nil {
+			continue
+		}
+		rolloutID, _ := flag["rolloutId"].(string)
+		rollout, ok := rollouts[rolloutID]
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("rollout of flag %q not found", flagKey))
+			continue
+		}
+		if *override.Enabled {
+			if !forceRolloutOn(rollout) {
+				warnings = append(warnings, fmt.Sprintf("rollout of flag %q has no enabled variation", flagKey))
+				continue
+			}
+			applied = append(applied, fmt.Sprintf("flag %q rollout forced to 100%%", flagKey))
+		} else {
+			for _, rule := range objects(rollout["experiments"]) {
+				rule["trafficAllocation"] = []interface{}{}
+			}
+			applied = append(applied, fmt.Sprintf("flag %q rollout forced to 0%%", flagKey))
+		}
+	}
+
+	experimentKeys := make([]string, 0, len(o.Experiments))
+	for experimentKey := range o.Experiments {
+		experimentKeys = append(experimentKeys, experimentKey)
+	}
+	sort.Strings(experimentKeys)
+	for _, experimentKey := range experimentKeys {
+		if !o.Experiments[experimentKey].Disabled {
+			continue
+		}
+		experiment, ok := experiments[experimentKey]
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("experiment %q not found", experimentKey))
+			continue
+		}
+		experiment["status"] = "Paused"
+		experiment["trafficAllocation"] = []interface{}{}
+		applied = append(applied, fmt.Sprintf("experiment %q disabled", experimentKey))
+	}
+
+	merged, err = json.Marshal(document)
+	return merged, applied, warnings, err
+}
+
+// forceRolloutOn replaces the rules of the rollout with a single rule serving an enabled variation to everyone
+func forceRolloutOn(rollout map[string]interface{}) bool {
+	rules := objects(rollout["experiments"])
+	// prefer the "Everyone Else" rule which is the last one
+	for i := len(rules) - 1; i >= 0; i-- {
+		for _, variation := range objects(rules[i]["variations"]) {
+			if enabled, _ := variation["featureEnabled"].(bool); !enabled {
+				continue
+			}
+			rule := rules[i]
+			rule["status"] = "Running"
+			rule["audienceIds"] = []interface{}{}
+			rule["audienceConditions"] = []interface{}{}
+			rule["variations"] = []interface{}{variation}
+			rule["trafficAllocation"] = []interface{}{map[string]interface{}{"entityId": variation["id"], "endOfRange": fullTrafficAllocation}}
+			delete(rule, "rampSchedule") // a ramp would hold back the users the rule is forced on for
+			rollout["experiments"] = []interface{}{rule}
+			return true
+		}
+	}
+	return false
+}
+
+// objects returns the JSON objects of a JSON array
+func objects(value interface{}) (result []map[string]interface{}) {
+	array, _ := value.([]interface{})
+	for _, item := range array {
+		if object, ok := item.(map[string]interface{}); ok {
+			result = append(result, object)
+		}
+	}
+	return result
+}
+
+// objectsByField indexes the JSON objects of a JSON array by a string field
+func objectsByField(value interface{}, field string) map[string]map[string]interface{} {
+	result := map[string]map[string]interface{}{}
+	for _, object := range objects(value) {
+		if key, ok := object[field].(string); ok {
+			result[key] = object
+		}
+	}
+	return result
+}
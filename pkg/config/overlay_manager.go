@@ -0,0 +1,141 @@
+/****************************************************************************
+ * Copyright 2026, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package config //
+package config
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/WolffunService/experiment/pkg/config/datafileprojectconfig"
+	"github.com/WolffunService/experiment/pkg/logging"
+	"github.com/WolffunService/experiment/pkg/notification"
+)
+
+// OverlayProjectConfigManager layers a ConfigOverlay on top of the config of another ProjectConfigManager. The overlay
+// is re-applied whenever the underlying manager sets a new revision. Should the merged datafile fail to parse, the
+// config of the underlying manager is returned unchanged.
+type OverlayProjectConfigManager struct {
+	base    ProjectConfigManager
+	overlay *ConfigOverlay
+	sdkKey  string
+	logger  logging.OptimizelyLogProducer
+
+	configLock       sync.Mutex
+	baseRevision     string
+	projectConfig    ProjectConfig
+	optimizelyConfig *OptimizelyConfig
+	applied          []string
+}
+
+// NewOverlayProjectConfigManager returns a config manager applying the overlay on top of the configs of the base manager
+func NewOverlayProjectConfigManager(sdkKey string, base ProjectConfigManager, overlay *ConfigOverlay) *OverlayProjectConfigManager {
+	return &OverlayProjectConfigManager{
+		base:    base,
+		overlay: overlay,
+		sdkKey:  sdkKey,
+		logger:  logging.GetLogger(sdkKey, "OverlayProjectConfigManager"),
+	}
+}
+
+// GetConfig returns the config of the base manager with the overlay applied
+func (cm *OverlayProjectConfigManager) GetConfig() (ProjectConfig, error) {
+	baseConfig, err := cm.base.GetConfig()
+	if err != nil || baseConfig == nil {
+		return baseConfig, err
+	}
+
+	cm.configLock.Lock()
+	defer cm.configLock.Unlock()
+	if cm.projectConfig == nil || cm.baseRevision != baseConfig.GetRevision() {
+		cm.merge(baseConfig)
+	}
+	return cm.projectConfig, nil
+}
+
+// GetOptimizelyConfig returns the optimizely config of the merged config, listing the applied overrides
+func (cm *OverlayProjectConfigManager) GetOptimizelyConfig() *OptimizelyConfig {
+	projectConfig, err := cm.GetConfig()
+	if err != nil || projectConfig == nil {
+		return nil
+	}
+
+	cm.configLock.Lock()
+	defer cm.configLock.Unlock()
+	if cm.optimizelyConfig == nil {
+		cm.optimizelyConfig = NewOptimizelyConfig(projectConfig)
+		cm.optimizelyConfig.Overrides = cm.applied
+	}
+	return cm.optimizelyConfig
+}
+
+// Overrides returns the descriptions of the overrides applied to the current config
+func (cm *OverlayProjectConfigManager) Overrides() []string {
+	if _, err := cm.GetConfig(); err != nil {
+		return nil
+	}
+	cm.configLock.Lock()
+	defer cm.configLock.Unlock()
+	return cm.applied
+}
+
+// OnProjectConfigUpdate registers a handler for the ProjectConfigUpdate notifications of the base manager
+func (cm *OverlayProjectConfigManager) OnProjectConfigUpdate(callback func(notification.ProjectConfigUpdateNotification)) (int, error) {
+	return cm.base.OnProjectConfigUpdate(callback)
+}
+
+// RemoveOnProjectConfigUpdate removes handler for ProjectConfigUpdate notification with given id
+func (cm *OverlayProjectConfigManager) RemoveOnProjectConfigUpdate(id int) error {
+	return cm.base.RemoveOnProjectConfigUpdate(id)
+}
+
+// Ready returns the readiness channel of the base manager, or nil if it does not signal readiness
+func (cm *OverlayProjectConfigManager) Ready() <-chan struct{} {
+	if readyNotifier, ok := cm.base.(ReadyNotifier); ok {
+		return readyNotifier.Ready()
+	}
+	return nil
+}
+
+// merge must be called with the config lock held
+func (cm *OverlayProjectConfigManager) merge(baseConfig ProjectConfig) {
+	cm.baseRevision = baseConfig.GetRevision()
+	cm.projectConfig = baseConfig
+	cm.optimizelyConfig = nil
+	cm.applied = nil
+
+	datafile, applied, warnings, err := cm.overlay.Apply([]byte(baseConfig.GetDatafile()))
+	for _, warning := range warnings {
+		cm.logger.Warning(fmt.Sprintf("Config overlay: %s", warning))
+	}
+	if err != nil {
+		cm.logger.Error("Unable to apply config overlay, using the datafile as is", err)
+		return
+	}
+	projectConfig, err := datafileprojectconfig.NewDatafileProjectConfig(datafile, logging.GetLogger(cm.sdkKey, "DatafileProjectConfig"))
+	if err != nil {
+		cm.logger.Error("Unable to parse the datafile with the config overlay, using the datafile as is", err)
+		return
+	}
+
+	cm.projectConfig = projectConfig
+	cm.applied = applied
+	if len(applied) > 0 {
+		cm.logger.Warning(fmt.Sprintf("Datafile revision %s is overridden locally: %s", cm.baseRevision, strings.Join(applied, "; ")))
+	}
+}
@@ -0,0 +1,165 @@
+/****************************************************************************
+ * Copyright 2026, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package config //
+package config
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/WolffunService/experiment/pkg/config/datafileprojectconfig"
+	"github.com/WolffunService/experiment/pkg/entities"
+	"github.com/WolffunService/experiment/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const overlayDatafile = `{
+	"revision": "%s", "version": "4",
+	"experiments": [{"id": "e1", "key": "exp_1", "layerId": "l1", "status": "Running", "audienceIds": [],
+		"variations": [{"id": "v1", "key": "a"}], "trafficAllocation": [{"entityId": "v1", "endOfRange": 10000}]}],
+	"groups": [{"id": "g1", "policy": "random", "trafficAllocation": [{"entityId": "e2", "endOfRange": 10000}],
+		"experiments": [{"id": "e2", "key": "exp_2", "layerId": "l2", "status": "Running", "audienceIds": [],
+			"variations": [{"id": "v2", "key": "b"}], "trafficAllocation": [{"entityId": "v2", "endOfRange": 10000}]}]}],
+	"rollouts": [
+		{"id": "r1", "experiments": [
+			{"id": "rule1", "key": "rule_1", "layerId": "r1", "status": "Running", "audienceIds": ["a1"],
+				"variations": [{"id": "v3", "key": "on", "featureEnabled": true}], "trafficAllocation": [{"entityId": "v3", "endOfRange": 500}]},
+			{"id": "rule2", "key": "rule_2", "layerId": "r1", "status": "Running", "audienceIds": [],
+				"variations": [{"id": "v4", "key": "off", "featureEnabled": false}], "trafficAllocation": [{"entityId": "v4", "endOfRange": 10000}]}]},
+		{"id": "r2", "experiments": [
+			{"id": "rule3", "key": "rule_3", "layerId": "r2", "status": "Running", "audienceIds": [],
+				"variations": [{"id": "v5", "key": "on", "featureEnabled": true}], "trafficAllocation": [{"entityId": "v5", "endOfRange": 10000}]}]}
+	],
+	"featureFlags": [
+		{"id": "f1", "key": "flag_1", "rolloutId": "r1", "experimentIds": [],
+			"variables": [{"id": "var1", "key": "color", "type": "string", "defaultValue": "red"}]},
+		{"id": "f2", "key": "flag_2", "rolloutId": "r2", "experimentIds": [], "variables": []}
+	]
+}`
+
+func overlayTestDatafile(revision string) []byte {
+	return []byte(strings.Replace(overlayDatafile, "%s", revision, 1))
+}
+
+func TestConfigOverlayApply(t *testing.T) {
+	overlay, err := ParseConfigOverlay([]byte(`{
+		"flags": {
+			"flag_1": {"enabled": true, "variables": {"color": "blue", "size": "1"}},
+			"flag_2": {"enabled": false},
+			"flag_3": {"enabled": true}
+		},
+		"experiments": {"exp_1": {"disabled": true}, "exp_2": {"disabled": true}, "exp_3": {"disabled": true}}
+	}`))
+	assert.NoError(t, err)
+
+	merged, applied, warnings, err := overlay.Apply(overlayTestDatafile("42"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		`flag "flag_1" variable "color" default set to "blue"`,
+		`flag "flag_1" rollout forced to 100%`,
+		`flag "flag_2" rollout forced to 0%`,
+		`experiment "exp_1" disabled`,
+		`experiment "exp_2" disabled`,
+	}, applied)
+	assert.Equal(t, []string{`variable "size" of flag "flag_1" not found`, `flag "flag_3" not found`, `experiment "exp_3" not found`}, warnings)
+
+	projectConfig, err := datafileprojectconfig.NewDatafileProjectConfig(merged, logger)
+	assert.NoError(t, err)
+	assert.Equal(t, "42", projectConfig.GetRevision())
+
+	variable, _ := projectConfig.GetVariableByKey("flag_1", "color")
+	assert.Equal(t, "blue", variable.DefaultValue)
+
+	flag1, _ := projectConfig.GetFeatureByKey("flag_1")
+	if assert.Len(t, flag1.Rollout.Experiments, 1) {
+		rule := flag1.Rollout.Experiments[0]
+		assert.Equal(t, "rule1", rule.ID)
+		assert.Empty(t, rule.AudienceIds)
+		assert.Equal(t, []entities.Range{{EntityID: "v3", EndOfRange: 10000}}, rule.TrafficAllocation)
+	}
+
+	flag2, _ := projectConfig.GetFeatureByKey("flag_2")
+	assert.Empty(t, flag2.Rollout.Experiments[0].TrafficAllocation)
+
+	for _, key := range []string{"exp_1", "exp_2"} {
+		experiment, _ := projectConfig.GetExperimentByKey(key)
+		assert.False(t, experiment.IsRunning())
+		assert.Empty(t, experiment.TrafficAllocation)
+	}
+}
+
+func TestConfigOverlayForcedRolloutDropsRampSchedule(t *testing.T) {
+	datafile := strings.Replace(string(overlayTestDatafile("42")), `"key": "rule_3", "layerId": "r2",`,
+		`"key": "rule_3", "layerId": "r2", "rampSchedule": [{"startTime": "2026-03-01T00:00:00Z", "percentage": 5}],`, 1)
+	projectConfig, err := datafileprojectconfig.NewDatafileProjectConfig([]byte(datafile), logger)
+	assert.NoError(t, err)
+	flag2, _ := projectConfig.GetFeatureByKey("flag_2")
+	assert.Len(t, flag2.Rollout.Experiments[0].RampSchedule, 1)
+
+	enabled := true
+	overlay := &ConfigOverlay{Flags: map[string]FlagOverride{"flag_2": {Enabled: &enabled}}}
+	merged, applied, _, err := overlay.Apply([]byte(datafile))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{`flag "flag_2" rollout forced to 100%`}, applied)
+
+	projectConfig, err = datafileprojectconfig.NewDatafileProjectConfig(merged, logger)
+	assert.NoError(t, err)
+	flag2, _ = projectConfig.GetFeatureByKey("flag_2")
+	if assert.Len(t, flag2.Rollout.Experiments, 1) {
+		rule := flag2.Rollout.Experiments[0]
+		assert.Empty(t, rule.RampSchedule)
+		assert.Equal(t, []entities.Range{{EntityID: "v5", EndOfRange: 10000}}, rule.TrafficAllocation)
+	}
+}
+
+func TestConfigOverlayApplyInvalidDatafile(t *testing.T) {
+	_, _, _, err := (&ConfigOverlay{}).Apply([]byte("NOT-VALID"))
+	assert.Error(t, err)
+
+	_, err = ParseConfigOverlay([]byte("NOT-VALID"))
+	assert.Error(t, err)
+}
+
+func TestOverlayProjectConfigManagerReappliesOverlayOnUpdate(t *testing.T) {
+	mockRequester := new(MockRequester)
+	mockRequester.On("Get", []utils.Header(nil)).Return(overlayTestDatafile("43"), http.Header{}, http.StatusOK, nil)
+	base := NewAsyncPollingProjectConfigManager("overlay_sdk_key", WithRequester(mockRequester), WithInitialDatafile(overlayTestDatafile("42")))
+
+	overlay := &ConfigOverlay{Flags: map[string]FlagOverride{"flag_1": {Variables: map[string]string{"color": "blue"}}}}
+	configManager := NewOverlayProjectConfigManager("overlay_sdk_key", base, overlay)
+
+	assert.Equal(t, "blue", variableDefault(t, configManager, "flag_1", "color"))
+	assert.Equal(t, []string{`flag "flag_1" variable "color" default set to "blue"`}, configManager.GetOptimizelyConfig().Overrides)
+	assert.Equal(t, "blue", configManager.GetOptimizelyConfig().FeaturesMap["flag_1"].VariablesMap["color"].Value)
+
+	base.SyncConfig()
+	assert.Equal(t, "43", currentRevision(configManager))
+	assert.Equal(t, "blue", variableDefault(t, configManager, "flag_1", "color"))
+	assert.Equal(t, "red", variableDefault(t, base, "flag_1", "color"))
+	assert.Equal(t, "43", configManager.GetOptimizelyConfig().Revision)
+	assert.Equal(t, base.Ready(), configManager.Ready())
+}
+
+func variableDefault(t *testing.T, configManager ProjectConfigManager, flagKey, variableKey string) string {
+	projectConfig, err := configManager.GetConfig()
+	assert.NoError(t, err)
+	variable, err := projectConfig.GetVariableByKey(flagKey, variableKey)
+	assert.NoError(t, err)
+	return variable.DefaultValue
+}
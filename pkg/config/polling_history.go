@@ -0,0 +1,127 @@
+/****************************************************************************
+ * Copyright 2026, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package config //
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultHistorySize sets default number of parsed configs kept by the PollingProjectConfigManager
+const DefaultHistorySize = 10
+
+// ConfigHistoryEntry is a previously fetched project config
+type ConfigHistoryEntry struct {
+	Revision  string
+	FetchedAt time.Time
+	Source    string // name of the datafile source which served the revision
+	Config    ProjectConfig
+}
+
+// History returns the last fetched configs, most recently fetched first. While a revision is pinned the most recently
+// fetched config is not necessarily the current one.
+func (cm *PollingProjectConfigManager) History() []ConfigHistoryEntry {
+	cm.configLock.RLock()
+	defer cm.configLock.RUnlock()
+	history := make([]ConfigHistoryEntry, len(cm.history))
+	copy(history, cm.history)
+	return history
+}
+
+// Pin sets the config of the given revision from the history and keeps it until Unpin is called, fetched updates are
+// still recorded in the history but not applied. The pin is not persisted.
+func (cm *PollingProjectConfigManager) Pin(revision string) error {
+	cm.configLock.Lock()
+	var pinnedConfig ProjectConfig
+	for _, entry := range cm.history {
+		if entry.Revision == revision {
+			pinnedConfig = entry.Config
+			break
+		}
+	}
+	if pinnedConfig == nil {
+		cm.configLock.Unlock()
+		return fmt.Errorf("revision %s is not in the config history", revision)
+	}
+
+	previousConfig := cm.projectConfig
+	cm.pinnedRevision = revision
+	cm.logger.Warning(fmt.Sprintf("Pinned datafile revision: %s, updates are suppressed until it is unpinned", revision))
+	if previousConfig != nil && previousConfig.GetRevision() == revision {
+		cm.configLock.Unlock()
+		return nil
+	}
+	err := cm.setConfig(pinnedConfig)
+	cm.configLock.Unlock()
+	if err == nil {
+		cm.sendConfigUpdateNotification(previousConfig, pinnedConfig)
+	}
+	return err
+}
+
+// Unpin stops suppressing updates and sets the most recently fetched config
+func (cm *PollingProjectConfigManager) Unpin() {
+	cm.configLock.Lock()
+	if cm.pinnedRevision == "" {
+		cm.configLock.Unlock()
+		return
+	}
+	cm.logger.Info(fmt.Sprintf("Unpinned datafile revision: %s", cm.pinnedRevision))
+	cm.pinnedRevision = ""
+
+	previousConfig := cm.projectConfig
+	if len(cm.history) == 0 || (previousConfig != nil && cm.history[0].Revision == previousConfig.GetRevision()) {
+		cm.configLock.Unlock()
+		return
+	}
+	latestConfig := cm.history[0].Config
+	err := cm.setConfig(latestConfig)
+	cm.configLock.Unlock()
+	if err == nil {
+		cm.logger.Debug(fmt.Sprintf("New datafile set with revision: %s. Old revision: %s", latestConfig.GetRevision(), previousConfig.GetRevision()))
+		cm.sendConfigUpdateNotification(previousConfig, latestConfig)
+	}
+}
+
+// PinnedRevision returns the pinned revision or an empty string if none is pinned
+func (cm *PollingProjectConfigManager) PinnedRevision() string {
+	cm.configLock.RLock()
+	defer cm.configLock.RUnlock()
+	return cm.pinnedRevision
+}
+
+// recordHistory must be called with the config lock held, it adds the config as the most recently fetched one and
+// returns false if its revision was already the most recently fetched
+func (cm *PollingProjectConfigManager) recordHistory(projectConfig ProjectConfig, fetchedAt time.Time, source string) bool {
+	if cm.historySize <= 0 {
+		return true
+	}
+	revision := projectConfig.GetRevision()
+	if len(cm.history) > 0 && cm.history[0].Revision == revision {
+		return false
+	}
+
+	history := []ConfigHistoryEntry{{Revision: revision, FetchedAt: fetchedAt, Source: source, Config: projectConfig}}
+	for _, entry := range cm.history {
+		if entry.Revision != revision && len(history) < cm.historySize {
+			history = append(history, entry)
+		}
+	}
+	cm.history = history
+	return true
+}
@@ -0,0 +1,111 @@
+/****************************************************************************
+ * Copyright 2026, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package config //
+package config
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/WolffunService/experiment/pkg/notification"
+	"github.com/WolffunService/experiment/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func historyRevisions(cm *PollingProjectConfigManager) (revisions []string) {
+	for _, entry := range cm.History() {
+		revisions = append(revisions, entry.Revision)
+	}
+	return revisions
+}
+
+func TestHistoryIsBounded(t *testing.T) {
+	mockRequester := new(MockRequester)
+	mockRequester.On("Get", []utils.Header(nil)).Return([]byte(`{"revision":"43","version": "4"}`), http.Header{}, http.StatusOK, nil).Once()
+	mockRequester.On("Get", []utils.Header(nil)).Return([]byte(`{"revision":"44","version": "4"}`), http.Header{}, http.StatusOK, nil).Once()
+
+	configManager := NewAsyncPollingProjectConfigManager("history_bounded", WithRequester(mockRequester), WithHistorySize(2),
+		WithInitialDatafile([]byte(`{"revision":"42","version": "4"}`)))
+	assert.Equal(t, []string{"42"}, historyRevisions(configManager))
+	assert.True(t, configManager.History()[0].FetchedAt.IsZero())
+
+	configManager.SyncConfig()
+	configManager.SyncConfig()
+	assert.Equal(t, []string{"44", "43"}, historyRevisions(configManager))
+	assert.False(t, configManager.History()[0].FetchedAt.IsZero())
+	mockRequester.AssertExpectations(t)
+}
+
+func TestPinSuppressesUpdatesUntilUnpinned(t *testing.T) {
+	mockRequester := new(MockRequester)
+	mockRequester.On("Get", []utils.Header(nil)).Return([]byte(`{"revision":"43","version": "4"}`), http.Header{}, http.StatusOK, nil).Once()
+	mockRequester.On("Get", []utils.Header(nil)).Return([]byte(`{"revision":"44","version": "4"}`), http.Header{}, http.StatusOK, nil).Once()
+
+	configManager := NewAsyncPollingProjectConfigManager("history_pin", WithRequester(mockRequester),
+		WithInitialDatafile([]byte(`{"revision":"42","version": "4"}`)))
+	var notifications []notification.ProjectConfigUpdateNotification
+	configManager.OnProjectConfigUpdate(func(n notification.ProjectConfigUpdateNotification) {
+		notifications = append(notifications, n)
+	})
+
+	configManager.SyncConfig()
+	assert.Equal(t, "43", currentRevision(configManager))
+
+	assert.Error(t, configManager.Pin("41"))
+	assert.NoError(t, configManager.Pin("42"))
+	assert.Equal(t, "42", currentRevision(configManager))
+	assert.Equal(t, "42", configManager.PinnedRevision())
+
+	// fetched revision is recorded but not applied
+	configManager.SyncConfig()
+	assert.Equal(t, "42", currentRevision(configManager))
+	assert.Equal(t, []string{"44", "43", "42"}, historyRevisions(configManager))
+
+	configManager.Unpin()
+	assert.Equal(t, "44", currentRevision(configManager))
+	assert.Equal(t, "", configManager.PinnedRevision())
+
+	if assert.Len(t, notifications, 3) {
+		assert.Equal(t, "43", notifications[0].Revision)
+		assert.Equal(t, "", notifications[0].PinnedRevision)
+		assert.Equal(t, "42", notifications[1].Revision)
+		assert.Equal(t, "43", notifications[1].OldRevision)
+		assert.Equal(t, "42", notifications[1].PinnedRevision)
+		assert.Equal(t, "44", notifications[2].Revision)
+		assert.Equal(t, "42", notifications[2].OldRevision)
+		assert.Equal(t, "", notifications[2].PinnedRevision)
+	}
+	mockRequester.AssertExpectations(t)
+}
+
+func TestUnpinKeepsPinnedRevisionWhenItIsFetchedAgain(t *testing.T) {
+	mockRequester := new(MockRequester)
+	mockRequester.On("Get", []utils.Header(nil)).Return([]byte(`{"revision":"43","version": "4"}`), http.Header{}, http.StatusOK, nil).Once()
+	mockRequester.On("Get", []utils.Header(nil)).Return([]byte(`{"revision":"42","version": "4"}`), http.Header{}, http.StatusOK, nil).Once()
+
+	configManager := NewAsyncPollingProjectConfigManager("history_refetch", WithRequester(mockRequester),
+		WithInitialDatafile([]byte(`{"revision":"42","version": "4"}`)))
+	configManager.SyncConfig()
+	assert.NoError(t, configManager.Pin("42"))
+
+	// the dashboard was rolled back to the pinned revision
+	configManager.SyncConfig()
+	configManager.Unpin()
+	assert.Equal(t, "42", currentRevision(configManager))
+	assert.Equal(t, []string{"42", "43"}, historyRevisions(configManager))
+}
@@ -19,18 +19,19 @@ package config
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"net/http"
+	"math"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/WolffunService/experiment/pkg/config/datafileprojectconfig"
 	"github.com/WolffunService/experiment/pkg/logging"
+	"github.com/WolffunService/experiment/pkg/metrics"
 	"github.com/WolffunService/experiment/pkg/notification"
 	"github.com/WolffunService/experiment/pkg/registry"
 	"github.com/WolffunService/experiment/pkg/utils"
-
-	"github.com/pkg/errors"
 )
 
 // DefaultPollingInterval sets default interval for polling manager
@@ -42,6 +43,15 @@ const ModifiedSince = "If-Modified-Since"
 // LastModified header key for response
 const LastModified = "Last-Modified"
 
+// IfNoneMatch header key for request
+const IfNoneMatch = "If-None-Match"
+
+// ETag header key for response
+const ETag = "ETag"
+
+// InitialDatafileSourceName identifies the datafile passed with WithInitialDatafile in the config history
+const InitialDatafileSourceName = "initial datafile"
+
 // DatafileURLTemplate is used to construct the endpoint for retrieving regular datafile from the CDN
 const DatafileURLTemplate = "https://cdn.optimizely.com/datafiles/%s.json"
 
@@ -51,28 +61,53 @@ const AuthDatafileURLTemplate = "https://config.optimizely.com/datafiles/auth/%s
 // Err403Forbidden is 403Forbidden specific error
 var Err403Forbidden = errors.New("unable to fetch fresh datafile (consider rechecking SDK key), status code: 403 Forbidden")
 
+// ErrDatafileParse is returned when the fetched datafile can not be parsed
+var ErrDatafileParse = errors.New("unable to parse datafile")
+
 // PollingProjectConfigManager maintains a dynamic copy of the project config by continuously polling for the datafile
 // from the Optimizely CDN at a given (configurable) interval.
 type PollingProjectConfigManager struct {
 	datafileURLTemplate string
 	initDatafile        []byte
-	lastModified        string
+	initSignature       []byte
 	notificationCenter  notification.Center
 	pollingInterval     time.Duration
 	requester           utils.Requester
 	sdkKey              string
 	logger              logging.OptimizelyLogProducer
 	datafileAccessToken string
+	validateDatafile    bool
+	datafileCache       *DatafileCache
+	verifier            DatafileVerifier
+	source              DatafileSource
+	metricsRegistry     metrics.Registry
+	schedule            pollingSchedule
+	historySize         int
 
 	configLock       sync.RWMutex
 	err              error
+	fetchedAt        time.Time
+	validators       map[string]DatafileValidators // cache validators by name of the source which issued them
 	projectConfig    ProjectConfig
+	sourcePriority   int // priority of the source which served the current config, see SyncConfig
 	optimizelyConfig *OptimizelyConfig
+	history          []ConfigHistoryEntry
+	pinnedRevision   string
+	ready            chan struct{}
+	readyOnce        sync.Once
 }
 
 // OptionFunc is used to provide custom configuration to the PollingProjectConfigManager.
 type OptionFunc func(*PollingProjectConfigManager)
 
+// WithNotificationCenter is an optional function, sets the notification center config updates are sent to, instead
+// of the one the registry holds for the SDK key
+func WithNotificationCenter(notificationCenter notification.Center) OptionFunc {
+	return func(p *PollingProjectConfigManager) {
+		p.notificationCenter = notificationCenter
+	}
+}
+
 // WithRequester is an optional function, sets a passed requester
 func WithRequester(requester utils.Requester) OptionFunc {
 	return func(p *PollingProjectConfigManager) {
@@ -94,6 +129,31 @@ func WithPollingInterval(interval time.Duration) OptionFunc {
 	}
 }
 
+// WithFailureBackoff is an optional function, after a failed fetch the next one is delayed by the initial interval,
+// growing exponentially with consecutive failures up to the max interval (the polling interval when max is zero)
+func WithFailureBackoff(initialInterval, maxInterval time.Duration) OptionFunc {
+	return func(p *PollingProjectConfigManager) {
+		p.schedule.backoffInitial = initialInterval
+		p.schedule.backoffMax = maxInterval
+	}
+}
+
+// WithPollingJitter is an optional function, randomizes every polling delay by up to the given fraction (e.g. 0.1 for +/-10%)
+// so that instances started together do not fetch the datafile in lock-step
+func WithPollingJitter(fraction float64) OptionFunc {
+	return func(p *PollingProjectConfigManager) {
+		p.schedule.jitter = math.Min(math.Max(fraction, 0), 1)
+	}
+}
+
+// WithInitialLoadSchedule is an optional function, sets the delays between fetches until the first datafile is loaded,
+// see DefaultInitialLoadSchedule
+func WithInitialLoadSchedule(delays ...time.Duration) OptionFunc {
+	return func(p *PollingProjectConfigManager) {
+		p.schedule.initialLoadSchedule = delays
+	}
+}
+
 // WithInitialDatafile is an optional function, sets a passed datafile
 func WithInitialDatafile(datafile []byte) OptionFunc {
 	return func(p *PollingProjectConfigManager) {
@@ -101,6 +161,14 @@ func WithInitialDatafile(datafile []byte) OptionFunc {
 	}
 }
 
+// WithInitialDatafileSignature is an optional function, sets the signature the initial datafile is verified with when
+// a datafile verifier is set
+func WithInitialDatafileSignature(signature []byte) OptionFunc {
+	return func(p *PollingProjectConfigManager) {
+		p.initSignature = signature
+	}
+}
+
 // WithDatafileAccessToken is an optional function, sets a passed datafile access token
 func WithDatafileAccessToken(datafileAccessToken string) OptionFunc {
 	return func(p *PollingProjectConfigManager) {
@@ -108,32 +176,73 @@ func WithDatafileAccessToken(datafileAccessToken string) OptionFunc {
 	}
 }
 
+// WithDatafileValidation is an optional function, rejects fetched, cached and initial datafiles that have fatal
+// validation issues
+func WithDatafileValidation() OptionFunc {
+	return func(p *PollingProjectConfigManager) {
+		p.validateDatafile = true
+	}
+}
+
+// WithDatafileSources is an optional function, sets the sources the datafile is fetched from instead of the datafile
+// URL template. The sources are tried in the given order until one of them succeeds. A datafile from a source of a
+// lower priority than the one which served the current config is only applied when its revision is newer.
+func WithDatafileSources(sources ...DatafileSource) OptionFunc {
+	return func(p *PollingProjectConfigManager) {
+		if len(sources) == 1 {
+			p.source = sources[0]
+			return
+		}
+		p.source = NewChainDatafileSource(logging.GetLogger(p.sdkKey, "ChainDatafileSource"), sources...)
+	}
+}
+
+// WithMetricsRegistry is an optional function, sets a registry the compressed and decompressed datafile sizes are reported to
+func WithMetricsRegistry(metricsRegistry metrics.Registry) OptionFunc {
+	return func(p *PollingProjectConfigManager) {
+		p.metricsRegistry = metricsRegistry
+	}
+}
+
+// WithDatafileVerifier is an optional function, sets a verifier of the datafile signature. The signature is read from
+// the DatafileSignatureHeader response header or else fetched from the datafile URL with the DatafileSignatureSuffix.
+// The initial datafile is verified with the signature passed with WithInitialDatafileSignature and the cached datafile
+// with the signature saved next to it, startup datafiles which can not be verified are not used.
+func WithDatafileVerifier(verifier DatafileVerifier) OptionFunc {
+	return func(p *PollingProjectConfigManager) {
+		p.verifier = verifier
+	}
+}
+
+// WithHistorySize is an optional function, sets how many of the last parsed configs are kept for History and Pin
+func WithHistorySize(size int) OptionFunc {
+	return func(p *PollingProjectConfigManager) {
+		p.historySize = size
+	}
+}
+
+// WithDatafileCache is an optional function, persists every fetched datafile at the given path and uses it as the
+// initial datafile on startup when no initial datafile is passed
+func WithDatafileCache(path string) OptionFunc {
+	return func(p *PollingProjectConfigManager) {
+		p.datafileCache = NewDatafileCache(path)
+	}
+}
+
 // SyncConfig downloads datafile and updates projectConfig
 func (cm *PollingProjectConfigManager) SyncConfig() {
-	var e error
-	var code int
-	var respHeaders http.Header
-	var datafile []byte
-
 	closeMutex := func(e error) {
 		cm.err = e
 		cm.configLock.Unlock()
 	}
 
-	url := fmt.Sprintf(cm.datafileURLTemplate, cm.sdkKey)
-	if cm.lastModified != "" {
-		lastModifiedHeader := utils.Header{Name: ModifiedSince, Value: cm.lastModified}
-		datafile, respHeaders, code, e = cm.requester.Get(url, lastModifiedHeader)
-	} else {
-		datafile, respHeaders, code, e = cm.requester.Get(url)
-	}
-
+	fetchedDatafile, e := cm.fetch()
 	if e != nil {
 		msg := "unable to fetch fresh datafile"
 		cm.logger.Warning(msg)
 		cm.configLock.Lock()
 
-		if code == http.StatusForbidden {
+		if e == Err403Forbidden {
 			closeMutex(Err403Forbidden)
 			return
 		}
@@ -142,42 +251,146 @@ func (cm *PollingProjectConfigManager) SyncConfig() {
 		return
 	}
 
-	if code == http.StatusNotModified {
+	if fetchedDatafile.NotModified {
 		cm.logger.Debug("The datafile was not modified and won't be downloaded again")
+		cm.configLock.Lock()
+		cm.fetchedAt = time.Now()
+		cm.err = nil
+		cm.configLock.Unlock()
 		return
 	}
 
-	// Save last-modified date from response header
+	sourceName := fetchedDatafile.Source.Name()
+	datafile, e := decompressDatafile(fetchedDatafile.Datafile, cm.metricsRegistry)
+	if e != nil {
+		cm.logger.Warning(fmt.Sprintf("unable to decompress datafile from %s", sourceName))
+		cm.configLock.Lock()
+		closeMutex(e)
+		return
+	}
+	fetchedDatafile.Datafile = datafile
+	if cm.verifier != nil {
+		if err := cm.verifySignature(fetchedDatafile); err != nil {
+			cm.logger.Error(fmt.Sprintf("Rejecting datafile from %s", sourceName), err)
+			cm.configLock.Lock()
+			closeMutex(err)
+			cm.sendDatafileRejectedNotification(sourceName, err)
+			return
+		}
+	}
+
 	cm.configLock.Lock()
-	lastModified := respHeaders.Get(LastModified)
-	if lastModified != "" {
-		cm.lastModified = lastModified
+	if cm.validateDatafile {
+		if err := cm.validate(datafile); err != nil {
+			closeMutex(err)
+			cm.sendDatafileRejectedNotification(sourceName, err)
+			return
+		}
 	}
 
 	projectConfig, err := datafileprojectconfig.NewDatafileProjectConfig(datafile, logging.GetLogger(cm.sdkKey, "NewDatafileProjectConfig"))
 	if err != nil {
 		cm.logger.Warning("failed to create project config")
-		closeMutex(errors.New("unable to parse datafile"))
+		closeMutex(ErrDatafileParse)
 		return
 	}
 
+	// Save cache validators from response headers once the datafile is known to be usable,
+	// otherwise a broken datafile would be answered with 304 Not Modified until it changes again
+	validators := cm.validators[sourceName]
+	if lastModified := fetchedDatafile.Validators.LastModified; lastModified != "" {
+		validators.LastModified = lastModified
+	}
+	if etag := fetchedDatafile.Validators.ETag; etag != "" {
+		validators.ETag = etag
+	}
+	cm.validators[sourceName] = validators
+
+	previousConfig := cm.projectConfig
 	var previousRevision string
-	if cm.projectConfig != nil {
-		previousRevision = cm.projectConfig.GetRevision()
+	if previousConfig != nil {
+		previousRevision = previousConfig.GetRevision()
+	}
+	// an outage of the primary source must not roll the config back to the older datafile of a fallback source
+	if previousConfig != nil && fetchedDatafile.Priority > cm.sourcePriority && projectConfig.GetRevision() != previousRevision &&
+		!isNewerRevision(projectConfig.GetRevision(), previousRevision) {
+		cm.logger.Warning(fmt.Sprintf("Ignoring revision %s from %s, it is older than the current revision %s", projectConfig.GetRevision(), sourceName, previousRevision))
+		closeMutex(nil)
+		return
 	}
+
+	fetchedAt := time.Now()
+	cm.fetchedAt = fetchedAt
+
+	isNewRevision := cm.recordHistory(projectConfig, fetchedAt, sourceName)
 	if projectConfig.GetRevision() == previousRevision {
+		if fetchedDatafile.Priority < cm.sourcePriority {
+			cm.sourcePriority = fetchedDatafile.Priority
+		}
 		cm.logger.Debug(fmt.Sprintf("No datafile updates. Current revision number: %s", cm.projectConfig.GetRevision()))
 		closeMutex(nil)
+		cm.persistDatafile(datafile, fetchedDatafile.Signature, previousRevision, fetchedAt)
+		return
+	}
+	if cm.pinnedRevision != "" {
+		if isNewRevision {
+			cm.logger.Info(fmt.Sprintf("Datafile revision %s is pinned, not applying revision: %s", cm.pinnedRevision, projectConfig.GetRevision()))
+		}
+		closeMutex(nil)
+		cm.persistDatafile(datafile, fetchedDatafile.Signature, projectConfig.GetRevision(), fetchedAt)
 		return
 	}
 	err = cm.setConfig(projectConfig)
+	if err == nil {
+		cm.sourcePriority = fetchedDatafile.Priority
+	}
 	closeMutex(err)
 	if err == nil {
-		cm.logger.Debug(fmt.Sprintf("New datafile set with revision: %s. Old revision: %s", projectConfig.GetRevision(), previousRevision))
-		cm.sendConfigUpdateNotification()
+		cm.logger.Debug(fmt.Sprintf("New datafile set with revision: %s from %s. Old revision: %s", projectConfig.GetRevision(), sourceName, previousRevision))
+		cm.persistDatafile(datafile, fetchedDatafile.Signature, projectConfig.GetRevision(), fetchedAt)
+		cm.sendConfigUpdateNotification(previousConfig, projectConfig)
 	}
 }
 
+// fetch fetches the datafile, passing each source the cache validators it issued
+func (cm *PollingProjectConfigManager) fetch() (*FetchedDatafile, error) {
+	cm.configLock.RLock()
+	validators := make(map[string]DatafileValidators, len(cm.validators))
+	for name, sourceValidators := range cm.validators {
+		validators[name] = sourceValidators
+	}
+	cm.configLock.RUnlock()
+
+	validatorsOf := func(source DatafileSource) DatafileValidators {
+		return validators[source.Name()]
+	}
+	source := cm.datafileSource()
+	chain, ok := source.(*ChainDatafileSource)
+	if !ok {
+		return source.Fetch(validatorsOf(source))
+	}
+	return chain.FetchWithValidators(validatorsOf)
+}
+
+// isNewerRevision returns whether the revision is newer than the current one, revisions which are not numbers are
+// never considered newer
+func isNewerRevision(revision, currentRevision string) bool {
+	number, err := strconv.Atoi(revision)
+	if err != nil {
+		return false
+	}
+	currentNumber, err := strconv.Atoi(currentRevision)
+	return err == nil && number > currentNumber
+}
+
+// datafileSource returns the configured datafile source or else the URL built from the datafile URL template
+func (cm *PollingProjectConfigManager) datafileSource() DatafileSource {
+	if cm.source != nil {
+		return cm.source
+	}
+	return NewHTTPDatafileSource(fmt.Sprintf(cm.datafileURLTemplate, cm.sdkKey), cm.requester)
+}
+
 // Start starts the polling
 func (cm *PollingProjectConfigManager) Start(ctx context.Context) {
 	if cm.pollingInterval <= 0 {
@@ -185,11 +398,13 @@ func (cm *PollingProjectConfigManager) Start(ctx context.Context) {
 		return
 	}
 	cm.logger.Debug("Polling Config Manager Initiated")
-	t := time.NewTicker(cm.pollingInterval)
+	t := time.NewTimer(cm.nextPollingDelay())
+	defer t.Stop()
 	for {
 		select {
 		case <-t.C:
 			cm.SyncConfig()
+			t.Reset(cm.nextPollingDelay())
 		case <-ctx.Done():
 			cm.logger.Debug("Polling Config Manager Stopped")
 			return
@@ -197,9 +412,21 @@ func (cm *PollingProjectConfigManager) Start(ctx context.Context) {
 	}
 }
 
+// nextPollingDelay returns the delay before the next fetch based on the outcome of the last one
+func (cm *PollingProjectConfigManager) nextPollingDelay() time.Duration {
+	cm.configLock.Lock()
+	defer cm.configLock.Unlock()
+	cm.schedule.interval = cm.pollingInterval
+	delay := cm.schedule.next(cm.err != nil, cm.projectConfig != nil)
+	if cm.err != nil {
+		cm.logger.Debug(fmt.Sprintf("Datafile fetch failed %d time(s) in a row, next attempt in %s", cm.schedule.failures, delay))
+	}
+	return delay
+}
+
 func (cm *PollingProjectConfigManager) setAuthHeaderIfDatafileAccessTokenPresent() {
 	if cm.datafileAccessToken != "" {
-		headers := []utils.Header{{Name: "Content-Type", Value: "application/json"}, {Name: "Accept", Value: "application/json"}}
+		headers := datafileRequestHeaders()
 		headers = append(headers, utils.Header{Name: "Authorization", Value: "Bearer " + cm.datafileAccessToken})
 		cm.requester = utils.NewHTTPRequester(logging.GetLogger(cm.sdkKey, "HTTPRequester"), utils.Headers(headers...))
 	}
@@ -209,7 +436,11 @@ func newConfigManager(sdkKey string, logger logging.OptimizelyLogProducer, confi
 	pollingProjectConfigManager := PollingProjectConfigManager{
 		notificationCenter: registry.GetNotificationCenter(sdkKey),
 		pollingInterval:    DefaultPollingInterval,
-		requester:          utils.NewHTTPRequester(logging.GetLogger(sdkKey, "HTTPRequester")),
+		historySize:        DefaultHistorySize,
+		validators:         map[string]DatafileValidators{},
+		requester:          utils.NewHTTPRequester(logging.GetLogger(sdkKey, "HTTPRequester"), utils.Headers(datafileRequestHeaders()...)),
+		metricsRegistry:    metrics.NewNoopRegistry(),
+		ready:              make(chan struct{}),
 		sdkKey:             sdkKey,
 		logger:             logger,
 	}
@@ -235,8 +466,9 @@ func NewPollingProjectConfigManager(sdkKey string, pollingMangerOptions ...Optio
 	pollingProjectConfigManager := newConfigManager(sdkKey, logging.GetLogger(sdkKey, "PollingProjectConfigManager"), pollingMangerOptions...)
 
 	if len(pollingProjectConfigManager.initDatafile) > 0 {
-		pollingProjectConfigManager.setInitialDatafile(pollingProjectConfigManager.initDatafile)
+		pollingProjectConfigManager.setInitialDatafile(pollingProjectConfigManager.initDatafile, pollingProjectConfigManager.initSignature, time.Time{}, InitialDatafileSourceName)
 	} else {
+		pollingProjectConfigManager.loadCachedDatafile()
 		pollingProjectConfigManager.SyncConfig() // initial poll
 	}
 	return pollingProjectConfigManager
@@ -247,7 +479,9 @@ func NewAsyncPollingProjectConfigManager(sdkKey string, pollingMangerOptions ...
 
 	pollingProjectConfigManager := newConfigManager(sdkKey, logging.GetLogger(sdkKey, "PollingProjectConfigManager"), pollingMangerOptions...)
 	if len(pollingProjectConfigManager.initDatafile) > 0 {
-		pollingProjectConfigManager.setInitialDatafile(pollingProjectConfigManager.initDatafile)
+		pollingProjectConfigManager.setInitialDatafile(pollingProjectConfigManager.initDatafile, pollingProjectConfigManager.initSignature, time.Time{}, InitialDatafileSourceName)
+	} else {
+		pollingProjectConfigManager.loadCachedDatafile()
 	}
 	return pollingProjectConfigManager
 }
@@ -262,6 +496,32 @@ func (cm *PollingProjectConfigManager) GetConfig() (ProjectConfig, error) {
 	return cm.projectConfig, nil
 }
 
+// DatafileValidators are the HTTP cache validators of the current datafile
+type DatafileValidators struct {
+	LastModified string
+	ETag         string
+}
+
+// GetDatafileValidators returns the cache validators sent as If-Modified-Since and If-None-Match on the next fetch
+// from the highest priority datafile source
+func (cm *PollingProjectConfigManager) GetDatafileValidators() DatafileValidators {
+	source := cm.datafileSource()
+	if chain, ok := source.(*ChainDatafileSource); ok && len(chain.sources) > 0 {
+		source = chain.sources[0]
+	}
+	cm.configLock.RLock()
+	defer cm.configLock.RUnlock()
+	return cm.validators[source.Name()]
+}
+
+// FetchedAt returns when the current datafile was last confirmed by the CDN. For a datafile loaded from the
+// datafile cache this is the time it was originally fetched, which can be used to report staleness.
+func (cm *PollingProjectConfigManager) FetchedAt() time.Time {
+	cm.configLock.RLock()
+	defer cm.configLock.RUnlock()
+	return cm.fetchedAt
+}
+
 // GetOptimizelyConfig returns the optimizely project config
 func (cm *PollingProjectConfigManager) GetOptimizelyConfig() *OptimizelyConfig {
 	cm.configLock.RLock()
@@ -308,27 +568,139 @@ func (cm *PollingProjectConfigManager) setConfig(projectConfig ProjectConfig) er
 	if cm.optimizelyConfig != nil {
 		cm.optimizelyConfig = NewOptimizelyConfig(projectConfig)
 	}
+	cm.readyOnce.Do(func() { close(cm.ready) })
 	return nil
 }
 
-func (cm *PollingProjectConfigManager) setInitialDatafile(datafile []byte) {
-	if len(datafile) != 0 {
-		cm.configLock.Lock()
-		defer cm.configLock.Unlock()
-		projectConfig, err := datafileprojectconfig.NewDatafileProjectConfig(datafile, logging.GetLogger(cm.sdkKey, "DatafileProjectConfig"))
-		if projectConfig != nil {
-			err = cm.setConfig(projectConfig)
-		}
+// Ready returns a channel which is closed once the first valid config is set
+func (cm *PollingProjectConfigManager) Ready() <-chan struct{} {
+	return cm.ready
+}
+
+func (cm *PollingProjectConfigManager) setInitialDatafile(datafile, signature []byte, fetchedAt time.Time, sourceName string) {
+	if len(datafile) == 0 {
+		return
+	}
+	// handlers may read the config, so the rejection is only sent once the lock is released
+	if rejection := cm.applyInitialDatafile(datafile, signature, fetchedAt, sourceName); rejection != nil {
+		cm.sendDatafileRejectedNotification(sourceName, rejection)
+	}
+}
+
+// applyInitialDatafile sets the config from the datafile, returning why the datafile was rejected by signature
+// verification or validation
+func (cm *PollingProjectConfigManager) applyInitialDatafile(datafile, signature []byte, fetchedAt time.Time, sourceName string) (rejection error) {
+	cm.configLock.Lock()
+	defer cm.configLock.Unlock()
+	datafile, err := decompressDatafile(datafile, cm.metricsRegistry)
+	if err != nil {
 		cm.err = err
+		return nil
+	}
+	if cm.verifier != nil {
+		if err := cm.verifier.Verify(datafile, signature); err != nil {
+			cm.logger.Error(fmt.Sprintf("Rejecting datafile from %s", sourceName), err)
+			cm.err = err
+			return err
+		}
+	}
+	if cm.validateDatafile {
+		if err := cm.validate(datafile); err != nil {
+			cm.err = err
+			return err
+		}
+	}
+	projectConfig, err := datafileprojectconfig.NewDatafileProjectConfig(datafile, logging.GetLogger(cm.sdkKey, "DatafileProjectConfig"))
+	if projectConfig != nil {
+		err = cm.setConfig(projectConfig)
+		cm.recordHistory(projectConfig, fetchedAt, sourceName)
+	}
+	cm.err = err
+	return nil
+}
+
+// loadCachedDatafile sets the last known good datafile from the datafile cache, if any
+func (cm *PollingProjectConfigManager) loadCachedDatafile() {
+	if cm.datafileCache == nil {
+		return
+	}
+	cachedDatafile, err := cm.datafileCache.Load()
+	if err != nil {
+		cm.logger.Info(fmt.Sprintf("No cached datafile loaded: %v", err))
+		return
+	}
+	cm.setInitialDatafile(cachedDatafile.Bytes(), cachedDatafile.Signature, cachedDatafile.FetchedAt, cm.datafileCache.path)
+
+	cm.configLock.Lock()
+	defer cm.configLock.Unlock()
+	if cm.projectConfig != nil {
+		cm.fetchedAt = cachedDatafile.FetchedAt
+		cm.logger.Info(fmt.Sprintf("Loaded cached datafile with revision: %s fetched at %s (%s ago)", cachedDatafile.Revision,
+			cachedDatafile.FetchedAt.Format(time.RFC3339), time.Since(cachedDatafile.FetchedAt).Round(time.Second)))
+	}
+}
+
+// persistDatafile saves the datafile to the datafile cache, if any
+func (cm *PollingProjectConfigManager) persistDatafile(datafile, signature []byte, revision string, fetchedAt time.Time) {
+	if cm.datafileCache == nil {
+		return
+	}
+	if err := cm.datafileCache.SaveSigned(datafile, signature, revision, fetchedAt); err != nil {
+		cm.logger.Warning(fmt.Sprintf("Unable to persist datafile to cache: %v", err))
+	}
+}
+
+// validate logs the validation issues of the datafile and returns an error if any of them is fatal
+func (cm *PollingProjectConfigManager) validate(datafile []byte) error {
+	issues := datafileprojectconfig.Validate(datafile)
+	for _, issue := range issues {
+		cm.logger.Warning(fmt.Sprintf("Datafile validation: %s", issue))
+	}
+	if datafileprojectconfig.HasFatal(issues) {
+		err := &datafileprojectconfig.ValidationError{Issues: issues}
+		cm.logger.Error("Rejecting datafile", err)
+		return err
+	}
+	return nil
+}
+
+// verifySignature checks the signature delivered with the datafile or else the detached signature from its source, which
+// is then kept with the fetched datafile
+func (cm *PollingProjectConfigManager) verifySignature(fetchedDatafile *FetchedDatafile) error {
+	signature := fetchedDatafile.Signature
+	if len(signature) == 0 {
+		signatureSource, ok := fetchedDatafile.Source.(SignatureSource)
+		if !ok {
+			return ErrMissingSignature
+		}
+		sidecar, err := signatureSource.FetchSignature()
+		if err != nil {
+			cm.logger.Warning(fmt.Sprintf("Unable to fetch datafile signature: %v", err))
+			return ErrMissingSignature
+		}
+		signature = sidecar
+		fetchedDatafile.Signature = sidecar
 	}
+	return cm.verifier.Verify(fetchedDatafile.Datafile, signature)
 }
 
-func (cm *PollingProjectConfigManager) sendConfigUpdateNotification() {
+func (cm *PollingProjectConfigManager) sendDatafileRejectedNotification(source string, reason error) {
 	if cm.notificationCenter != nil {
-		projectConfigUpdateNotification := notification.ProjectConfigUpdateNotification{
-			Type:     notification.ProjectConfigUpdate,
-			Revision: cm.projectConfig.GetRevision(),
+		datafileRejectedNotification := notification.DatafileRejectedNotification{
+			Type:   notification.DatafileRejected,
+			Source: source,
+			Reason: reason.Error(),
+		}
+		if err := cm.notificationCenter.Send(notification.DatafileRejected, datafileRejectedNotification); err != nil {
+			cm.logger.Warning("Problem with sending notification")
 		}
+	}
+}
+
+func (cm *PollingProjectConfigManager) sendConfigUpdateNotification(oldConfig, newConfig ProjectConfig) {
+	if cm.notificationCenter != nil {
+		projectConfigUpdateNotification := newProjectConfigUpdateNotification(oldConfig, newConfig)
+		projectConfigUpdateNotification.PinnedRevision = cm.PinnedRevision()
 		if err := cm.notificationCenter.Send(notification.ProjectConfigUpdate, projectConfigUpdateNotification); err != nil {
 			cm.logger.Warning("Problem with sending notification")
 		}
@@ -18,7 +18,11 @@ package config
 
 import (
 	"context"
+	"errors"
+	"io/ioutil"
 	"net/http"
+	"os"
+	"path/filepath"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -26,6 +30,7 @@ import (
 	"github.com/WolffunService/experiment/pkg/config/datafileprojectconfig"
 	"github.com/WolffunService/experiment/pkg/logging"
 	"github.com/WolffunService/experiment/pkg/notification"
+	"github.com/WolffunService/experiment/pkg/registry"
 	"github.com/WolffunService/experiment/pkg/utils"
 
 	"github.com/stretchr/testify/assert"
@@ -121,6 +126,125 @@ func TestSyncConfigFetchesDatafileUsingRequester(t *testing.T) {
 	assert.Equal(t, projectConfig, actual)
 }
 
+func TestSyncConfigRejectsInvalidDatafileWithValidation(t *testing.T) {
+	validDatafile := []byte(`{"revision":"42","version": "4"}`)
+	invalidDatafile := []byte(`{"revision":"43","version": "4","featureFlags":[{"id":"1","key":"flag","rolloutId":"missing"}]}`)
+	mockRequester := new(MockRequester)
+	mockRequester.On("Get", []utils.Header(nil)).Return(invalidDatafile, http.Header{}, http.StatusOK, nil)
+
+	sdkKey := "test_sdk_key"
+	configManager := NewAsyncPollingProjectConfigManager(sdkKey, WithRequester(mockRequester), WithInitialDatafile(validDatafile), WithDatafileValidation())
+	configManager.SyncConfig()
+	mockRequester.AssertExpectations(t)
+
+	actual, err := configManager.GetConfig()
+	assert.Nil(t, err)
+	assert.Equal(t, "42", actual.GetRevision())
+	assert.IsType(t, &datafileprojectconfig.ValidationError{}, configManager.err)
+}
+
+func TestSyncConfigAcceptsInvalidDatafileWithoutValidation(t *testing.T) {
+	invalidDatafile := []byte(`{"revision":"43","version": "4","featureFlags":[{"id":"1","key":"flag","rolloutId":"missing"}]}`)
+	mockRequester := new(MockRequester)
+	mockRequester.On("Get", []utils.Header(nil)).Return(invalidDatafile, http.Header{}, http.StatusOK, nil)
+
+	sdkKey := "test_sdk_key"
+	configManager := NewAsyncPollingProjectConfigManager(sdkKey, WithRequester(mockRequester))
+	configManager.SyncConfig()
+
+	actual, err := configManager.GetConfig()
+	assert.Nil(t, err)
+	assert.Equal(t, "43", actual.GetRevision())
+}
+
+func TestNewPollingProjectConfigManagerWithDatafileCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "polling_manager_test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	cachePath := filepath.Join(dir, "datafile.cache")
+
+	mockDatafile := []byte(`{"revision":"42","version": "4"}`)
+	mockRequester := new(MockRequester)
+	mockRequester.On("Get", []utils.Header(nil)).Return(mockDatafile, http.Header{}, http.StatusOK, nil)
+
+	sdkKey := "test_sdk_key"
+	configManager := NewPollingProjectConfigManager(sdkKey, WithRequester(mockRequester), WithDatafileCache(cachePath))
+	fetchedAt := configManager.FetchedAt()
+	assert.False(t, fetchedAt.IsZero())
+
+	cachedDatafile, err := NewDatafileCache(cachePath).Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "42", cachedDatafile.Revision)
+	assert.True(t, fetchedAt.Equal(cachedDatafile.FetchedAt))
+
+	// CDN is unreachable on the next cold start
+	failingRequester := new(MockRequester)
+	failingRequester.On("Get", []utils.Header(nil)).Return([]byte{}, http.Header{}, http.StatusInternalServerError, errors.New("unreachable"))
+	configManager = NewPollingProjectConfigManager(sdkKey, WithRequester(failingRequester), WithDatafileCache(cachePath))
+	failingRequester.AssertExpectations(t)
+
+	actual, err := configManager.GetConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "42", actual.GetRevision())
+	assert.True(t, fetchedAt.Equal(configManager.FetchedAt()))
+}
+
+func TestNewAsyncPollingProjectConfigManagerPrefersInitialDatafileOverCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "polling_manager_test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	cachePath := filepath.Join(dir, "datafile.cache")
+	assert.NoError(t, NewDatafileCache(cachePath).Save([]byte(`{"revision":"41","version": "4"}`), "41", time.Now()))
+
+	configManager := NewAsyncPollingProjectConfigManager("test_sdk_key", WithDatafileCache(cachePath))
+	actual, err := configManager.GetConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "41", actual.GetRevision())
+
+	configManager = NewAsyncPollingProjectConfigManager("test_sdk_key", WithDatafileCache(cachePath), WithInitialDatafile([]byte(`{"revision":"42","version": "4"}`)))
+	actual, err = configManager.GetConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "42", actual.GetRevision())
+}
+
+func TestCachedDatafileIsValidated(t *testing.T) {
+	dir, err := ioutil.TempDir("", "polling_manager_test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	cachePath := filepath.Join(dir, "datafile.cache")
+	invalidDatafile := []byte(`{"revision":"43","version": "4","featureFlags":[{"id":"1","key":"flag","rolloutId":"missing"}]}`)
+	assert.NoError(t, NewDatafileCache(cachePath).Save(invalidDatafile, "43", time.Now()))
+
+	configManager := NewAsyncPollingProjectConfigManager("test_sdk_key", WithDatafileCache(cachePath), WithDatafileValidation())
+	actual, err := configManager.GetConfig()
+	assert.Nil(t, actual)
+	assert.IsType(t, &datafileprojectconfig.ValidationError{}, err)
+
+	configManager = NewAsyncPollingProjectConfigManager("test_sdk_key", WithDatafileCache(cachePath))
+	assert.Equal(t, "43", currentRevision(configManager))
+}
+
+func TestNextPollingDelayBacksOffAfterFailures(t *testing.T) {
+	mockRequester := new(MockRequester)
+	mockRequester.On("Get", []utils.Header(nil)).Return([]byte{}, http.Header{}, http.StatusInternalServerError, errors.New("unreachable"))
+
+	configManager := NewAsyncPollingProjectConfigManager("test_sdk_key", WithRequester(mockRequester), WithPollingInterval(time.Minute),
+		WithFailureBackoff(time.Second, 0), WithPollingJitter(2), WithInitialLoadSchedule(100*time.Millisecond))
+	configManager.schedule.random = func() float64 { return 0.5 }
+	assert.Equal(t, 1.0, configManager.schedule.jitter)
+
+	configManager.SyncConfig()
+	assert.Equal(t, 100*time.Millisecond, configManager.nextPollingDelay())
+	configManager.SyncConfig()
+	assert.Equal(t, 2*time.Second, configManager.nextPollingDelay())
+
+	mockRequester = new(MockRequester)
+	mockRequester.On("Get", []utils.Header(nil)).Return([]byte(`{"revision":"42","version": "4"}`), http.Header{}, http.StatusOK, nil)
+	configManager.requester = mockRequester
+	configManager.SyncConfig()
+	assert.Equal(t, time.Minute, configManager.nextPollingDelay())
+}
+
 func TestNewPollingProjectConfigManagerWithNull(t *testing.T) {
 	mockDatafile := []byte("NOT-VALID")
 	mockRequester := new(MockRequester)
@@ -149,6 +273,28 @@ func TestNewAsyncPollingProjectConfigManagerWithNullDatafile(t *testing.T) {
 	mockRequester.AssertExpectations(t)
 }
 
+func TestPollingProjectConfigManagerWithNotificationCenter(t *testing.T) {
+	mockRequester := new(MockRequester)
+	mockRequester.On("Get", []utils.Header(nil)).Return([]byte(`{"revision":"43","version": "4"}`), http.Header{}, http.StatusOK, nil)
+	notificationCenter := notification.NewNotificationCenter()
+	configManager := NewPollingProjectConfigManager("notification_center_sdk_key", WithRequester(mockRequester),
+		WithInitialDatafile([]byte(`{"revision":"42","version": "4"}`)), WithNotificationCenter(notificationCenter))
+
+	revisions := []string{}
+	_, err := configManager.OnProjectConfigUpdate(func(n notification.ProjectConfigUpdateNotification) {
+		revisions = append(revisions, n.Revision)
+	})
+	assert.NoError(t, err)
+	registryUpdates := 0
+	registry.GetNotificationCenter("notification_center_sdk_key").AddHandler(notification.ProjectConfigUpdate, func(interface{}) {
+		registryUpdates++
+	})
+
+	configManager.SyncConfig()
+	assert.Equal(t, []string{"43"}, revisions)
+	assert.Zero(t, registryUpdates)
+}
+
 func TestNewPollingProjectConfigManagerWithSimilarDatafileRevisions(t *testing.T) {
 	// Test newer datafile should not replace the older one if revisions are the same
 	mockDatafile1 := []byte(`{"revision":"42","botFiltering":true,"version": "4"}`)
@@ -271,6 +417,60 @@ func TestNewAsyncPollingProjectConfigManagerWithLastModifiedDates(t *testing.T)
 	mockRequester.AssertExpectations(t)
 }
 
+func TestSyncConfigWithETag(t *testing.T) {
+	mockDatafile := []byte(`{"revision":"42","version": "4"}`)
+	mockRequester := new(MockRequester)
+	etag := `"5d8f2a"`
+	responseHeaders := http.Header{}
+	responseHeaders.Set(ETag, etag)
+
+	mockRequester.On("Get", []utils.Header(nil)).Return(mockDatafile, responseHeaders, http.StatusOK, nil)
+	mockRequester.On("Get", []utils.Header{{Name: IfNoneMatch, Value: etag}}).Return([]byte{}, responseHeaders, http.StatusNotModified, nil)
+
+	configManager := NewAsyncPollingProjectConfigManager("test_sdk_key", WithRequester(mockRequester))
+	configManager.SyncConfig()
+	assert.Equal(t, DatafileValidators{ETag: etag}, configManager.GetDatafileValidators())
+
+	configManager.SyncConfig()
+	actual, err := configManager.GetConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "42", actual.GetRevision())
+	mockRequester.AssertExpectations(t)
+}
+
+func TestSyncConfigWithETagAndLastModified(t *testing.T) {
+	mockDatafile := []byte(`{"revision":"42","version": "4"}`)
+	mockRequester := new(MockRequester)
+	etag := `W/"5d8f2a"`
+	modifiedDate := "Wed, 16 Oct 2019 20:16:45 GMT"
+	responseHeaders := http.Header{}
+	responseHeaders.Set(ETag, etag)
+	responseHeaders.Set(LastModified, modifiedDate)
+
+	mockRequester.On("Get", []utils.Header(nil)).Return(mockDatafile, responseHeaders, http.StatusOK, nil)
+	mockRequester.On("Get", []utils.Header{{Name: ModifiedSince, Value: modifiedDate}, {Name: IfNoneMatch, Value: etag}}).Return([]byte{}, http.Header{}, http.StatusNotModified, nil)
+
+	configManager := NewAsyncPollingProjectConfigManager("test_sdk_key", WithRequester(mockRequester))
+	configManager.SyncConfig()
+	configManager.SyncConfig()
+	assert.Equal(t, DatafileValidators{LastModified: modifiedDate, ETag: etag}, configManager.GetDatafileValidators())
+	mockRequester.AssertExpectations(t)
+}
+
+func TestSyncConfigDoesNotKeepValidatorsOfInvalidDatafile(t *testing.T) {
+	mockRequester := new(MockRequester)
+	responseHeaders := http.Header{}
+	responseHeaders.Set(ETag, `"broken"`)
+	responseHeaders.Set(LastModified, "Wed, 16 Oct 2019 20:16:45 GMT")
+	mockRequester.On("Get", []utils.Header(nil)).Return([]byte(`NOT-VALID`), responseHeaders, http.StatusOK, nil)
+
+	configManager := NewAsyncPollingProjectConfigManager("test_sdk_key", WithRequester(mockRequester))
+	configManager.SyncConfig()
+	assert.Equal(t, DatafileValidators{}, configManager.GetDatafileValidators())
+	configManager.SyncConfig()
+	mockRequester.AssertNumberOfCalls(t, "Get", 2)
+}
+
 func TestNewPollingProjectConfigManagerWithDifferentDatafileRevisions(t *testing.T) {
 	// Test newer datafile should replace the older one if revisions are different
 	mockDatafile1 := []byte(`{"revision":"42","botFiltering":true,"version": "4"}`)
@@ -677,3 +877,27 @@ func TestSetDatafileAccessTokenRequest(t *testing.T) {
 	assert.NotEqual(t, configManagerRequester, configManager.requester)
 	assert.NotEqual(t, asyncConfigManagerRequester, asyncConfigManager.requester)
 }
+
+func TestReadyIsClosedWhenFirstConfigIsSet(t *testing.T) {
+	mockRequester := new(MockRequester)
+	mockRequester.On("Get", []utils.Header(nil)).Return([]byte(`NOT-VALID`), http.Header{}, http.StatusOK, nil).Once()
+	mockRequester.On("Get", []utils.Header(nil)).Return([]byte(`{"revision":"42","version": "4"}`), http.Header{}, http.StatusOK, nil).Once()
+
+	configManager := NewAsyncPollingProjectConfigManager("ready_sdk_key", WithRequester(mockRequester))
+	configManager.SyncConfig()
+	_, err := configManager.GetConfig()
+	assert.Equal(t, ErrDatafileParse, err)
+	select {
+	case <-configManager.Ready():
+		t.Error("config manager is ready without a config")
+	default:
+	}
+
+	configManager.SyncConfig()
+	select {
+	case <-configManager.Ready():
+	default:
+		t.Error("config manager is not ready after the config was set")
+	}
+	mockRequester.AssertExpectations(t)
+}
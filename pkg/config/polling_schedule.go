@@ -0,0 +1,92 @@
+/****************************************************************************
+ * Copyright 2026, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package config //
+package config
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// DefaultBackoffMultiplier sets default growth factor of the delay between consecutive failed datafile fetches
+const DefaultBackoffMultiplier = 2.0
+
+// DefaultInitialLoadSchedule is a fast-path retry schedule used until the first datafile is loaded
+var DefaultInitialLoadSchedule = []time.Duration{1 * time.Second, 2 * time.Second, 5 * time.Second, 10 * time.Second}
+
+// pollingSchedule computes the delay before the next datafile fetch
+type pollingSchedule struct {
+	interval            time.Duration
+	jitter              float64 // fraction of each delay that is randomized, in [0, 1]
+	backoffInitial      time.Duration
+	backoffMax          time.Duration
+	backoffMultiplier   float64
+	initialLoadSchedule []time.Duration
+
+	failures            int
+	initialLoadAttempts int
+	random              func() float64
+}
+
+// next returns the delay before the next fetch given the outcome of the previous one
+func (s *pollingSchedule) next(failed, loaded bool) time.Duration {
+	if failed {
+		s.failures++
+	} else {
+		s.failures = 0
+	}
+
+	switch {
+	case !loaded && s.initialLoadAttempts < len(s.initialLoadSchedule):
+		delay := s.initialLoadSchedule[s.initialLoadAttempts]
+		s.initialLoadAttempts++
+		return s.withJitter(delay)
+	case s.failures > 0 && s.backoffInitial > 0:
+		return s.withJitter(s.backoffDelay())
+	default:
+		return s.withJitter(s.interval)
+	}
+}
+
+func (s *pollingSchedule) backoffDelay() time.Duration {
+	maxDelay := s.backoffMax
+	if maxDelay <= 0 {
+		maxDelay = s.interval
+	}
+	multiplier := s.backoffMultiplier
+	if multiplier < 1 {
+		multiplier = DefaultBackoffMultiplier
+	}
+	delay := float64(s.backoffInitial) * math.Pow(multiplier, float64(s.failures-1))
+	if delay > float64(maxDelay) {
+		return maxDelay
+	}
+	return time.Duration(delay)
+}
+
+// withJitter spreads the delay uniformly over [delay*(1-jitter), delay*(1+jitter)]
+func (s *pollingSchedule) withJitter(delay time.Duration) time.Duration {
+	if s.jitter <= 0 {
+		return delay
+	}
+	random := s.random
+	if random == nil {
+		random = rand.Float64
+	}
+	return time.Duration(float64(delay) * (1 - s.jitter + 2*s.jitter*random()))
+}
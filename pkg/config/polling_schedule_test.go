@@ -0,0 +1,67 @@
+/****************************************************************************
+ * Copyright 2026, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package config //
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPollingScheduleSteadyState(t *testing.T) {
+	schedule := pollingSchedule{interval: time.Minute}
+	assert.Equal(t, time.Minute, schedule.next(false, true))
+	// failures keep the polling interval when no backoff is configured
+	assert.Equal(t, time.Minute, schedule.next(true, true))
+}
+
+func TestPollingScheduleFailureBackoff(t *testing.T) {
+	schedule := pollingSchedule{interval: time.Minute, backoffInitial: 10 * time.Second, backoffMax: 35 * time.Second}
+	assert.Equal(t, 10*time.Second, schedule.next(true, true))
+	assert.Equal(t, 20*time.Second, schedule.next(true, true))
+	assert.Equal(t, 35*time.Second, schedule.next(true, true))
+	assert.Equal(t, 35*time.Second, schedule.next(true, true))
+	// success resets the backoff
+	assert.Equal(t, time.Minute, schedule.next(false, true))
+	assert.Equal(t, 10*time.Second, schedule.next(true, true))
+}
+
+func TestPollingScheduleBackoffCappedAtInterval(t *testing.T) {
+	schedule := pollingSchedule{interval: 30 * time.Second, backoffInitial: 20 * time.Second, backoffMultiplier: 3}
+	assert.Equal(t, 20*time.Second, schedule.next(true, true))
+	assert.Equal(t, 30*time.Second, schedule.next(true, true))
+}
+
+func TestPollingScheduleInitialLoad(t *testing.T) {
+	schedule := pollingSchedule{interval: time.Minute, backoffInitial: 10 * time.Second, initialLoadSchedule: []time.Duration{time.Second, 2 * time.Second}}
+	assert.Equal(t, time.Second, schedule.next(true, false))
+	assert.Equal(t, 2*time.Second, schedule.next(true, false))
+	// the schedule is exhausted, fall back to the failure backoff
+	assert.Equal(t, 40*time.Second, schedule.next(true, false))
+}
+
+func TestPollingScheduleJitter(t *testing.T) {
+	random := 0.0
+	schedule := pollingSchedule{interval: 100 * time.Second, jitter: 0.1, random: func() float64 { return random }}
+	assert.Equal(t, 90*time.Second, schedule.next(false, true))
+	random = 0.5
+	assert.Equal(t, 100*time.Second, schedule.next(false, true))
+	random = 1
+	assert.Equal(t, 110*time.Second, schedule.next(false, true))
+}
@@ -0,0 +1,86 @@
+/****************************************************************************
+ * Copyright 2026, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package config //
+package config
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// DatafileSignatureHeader is the response header carrying the detached signature of the datafile
+const DatafileSignatureHeader = "X-Datafile-Signature"
+
+// DatafileSignatureSuffix is appended to the datafile URL or path to locate the sidecar signature file
+const DatafileSignatureSuffix = ".sig"
+
+// ErrMissingSignature is returned when a datafile has to be verified but no signature was found
+var ErrMissingSignature = errors.New("datafile signature is missing")
+
+// ErrInvalidSignature is returned when the signature does not match the datafile
+var ErrInvalidSignature = errors.New("datafile signature is invalid")
+
+// DatafileVerifier verifies the detached signature of a datafile before it is parsed
+type DatafileVerifier interface {
+	Verify(datafile, signature []byte) error
+}
+
+// HMACVerifier verifies HMAC-SHA256 datafile signatures
+type HMACVerifier struct {
+	key []byte
+}
+
+// NewHMACVerifier returns a verifier of HMAC-SHA256 signatures made with the given shared key
+func NewHMACVerifier(key []byte) *HMACVerifier {
+	return &HMACVerifier{key: key}
+}
+
+// Verify checks the base64 or hex encoded HMAC-SHA256 signature of the datafile
+func (v *HMACVerifier) Verify(datafile, signature []byte) error {
+	sig, err := decodeSignature(signature)
+	if err != nil {
+		return err
+	}
+	mac := hmac.New(sha256.New, v.key)
+	mac.Write(datafile)
+	if !hmac.Equal(mac.Sum(nil), sig) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// decodeSignature decodes a base64 or hex encoded signature
+func decodeSignature(signature []byte) ([]byte, error) {
+	encoded := strings.TrimSpace(string(signature))
+	if encoded == "" {
+		return nil, ErrMissingSignature
+	}
+	if sig, err := hex.DecodeString(encoded); err == nil {
+		return sig, nil
+	}
+	if sig, err := base64.StdEncoding.DecodeString(encoded); err == nil {
+		return sig, nil
+	}
+	if sig, err := base64.RawURLEncoding.DecodeString(encoded); err == nil {
+		return sig, nil
+	}
+	return nil, ErrInvalidSignature
+}
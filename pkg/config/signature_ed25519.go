@@ -0,0 +1,51 @@
+//go:build go1.13
+// +build go1.13
+
+/****************************************************************************
+ * Copyright 2026, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package config //
+package config
+
+import (
+	"crypto/ed25519"
+	"fmt"
+)
+
+// Ed25519Verifier verifies Ed25519 datafile signatures
+type Ed25519Verifier struct {
+	publicKey ed25519.PublicKey
+}
+
+// NewEd25519Verifier returns a verifier of Ed25519 signatures made with the private key of the given public key
+func NewEd25519Verifier(publicKey []byte) (*Ed25519Verifier, error) {
+	if len(publicKey) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid Ed25519 public key size: %d", len(publicKey))
+	}
+	return &Ed25519Verifier{publicKey: ed25519.PublicKey(publicKey)}, nil
+}
+
+// Verify checks the base64 or hex encoded Ed25519 signature of the datafile
+func (v *Ed25519Verifier) Verify(datafile, signature []byte) error {
+	sig, err := decodeSignature(signature)
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(v.publicKey, datafile, sig) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+//go:build go1.13
+// +build go1.13
+
+/****************************************************************************
+ * Copyright 2026, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package config //
+package config
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEd25519Verifier(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	assert.NoError(t, err)
+	datafile := []byte(`{"revision":"42","version": "4"}`)
+	signature := []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, datafile)))
+
+	verifier, err := NewEd25519Verifier(publicKey)
+	assert.NoError(t, err)
+	assert.NoError(t, verifier.Verify(datafile, signature))
+	assert.Equal(t, ErrInvalidSignature, verifier.Verify([]byte(`{"revision":"43","version": "4"}`), signature))
+	assert.Equal(t, ErrMissingSignature, verifier.Verify(datafile, []byte(" ")))
+
+	_, err = NewEd25519Verifier([]byte("short"))
+	assert.Error(t, err)
+}
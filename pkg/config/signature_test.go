@@ -0,0 +1,219 @@
+/****************************************************************************
+ * Copyright 2026, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package config //
+package config
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/WolffunService/experiment/pkg/logging"
+	"github.com/WolffunService/experiment/pkg/notification"
+	"github.com/WolffunService/experiment/pkg/registry"
+	"github.com/WolffunService/experiment/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var signingKey = []byte("signing_key")
+
+func hmacSignature(datafile string) []byte {
+	mac := hmac.New(sha256.New, signingKey)
+	mac.Write([]byte(datafile))
+	return mac.Sum(nil)
+}
+
+func TestHMACVerifier(t *testing.T) {
+	datafile := `{"revision":"42","version": "4"}`
+	verifier := NewHMACVerifier(signingKey)
+
+	assert.NoError(t, verifier.Verify([]byte(datafile), []byte(base64.StdEncoding.EncodeToString(hmacSignature(datafile)))))
+	assert.NoError(t, verifier.Verify([]byte(datafile), []byte(hex.EncodeToString(hmacSignature(datafile))+"\n")))
+	assert.NoError(t, verifier.Verify([]byte(datafile), []byte(base64.RawURLEncoding.EncodeToString(hmacSignature(datafile)))))
+
+	assert.Equal(t, ErrInvalidSignature, verifier.Verify([]byte(`{"revision":"43","version": "4"}`), []byte(hex.EncodeToString(hmacSignature(datafile)))))
+	assert.Equal(t, ErrInvalidSignature, verifier.Verify([]byte(datafile), []byte("not a signature!")))
+	assert.Equal(t, ErrMissingSignature, verifier.Verify([]byte(datafile), nil))
+}
+
+func newSignedDatafileServer(datafile string, headerSignature, sidecarSignature []byte) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, DatafileSignatureSuffix) {
+			if sidecarSignature == nil {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write([]byte(hex.EncodeToString(sidecarSignature)))
+			return
+		}
+		if headerSignature != nil {
+			w.Header().Set(DatafileSignatureHeader, base64.StdEncoding.EncodeToString(headerSignature))
+		}
+		fmt.Fprint(w, datafile)
+	}))
+}
+
+func TestSyncConfigVerifiesSignature(t *testing.T) {
+	datafile := `{"revision":"42","version": "4"}`
+	requester := WithRequester(utils.NewHTTPRequester(logging.GetLogger("", "HTTPRequester"), utils.Retries(1)))
+
+	headerServer := newSignedDatafileServer(datafile, hmacSignature(datafile), nil)
+	defer headerServer.Close()
+	configManager := NewPollingProjectConfigManager("signature_header", requester, WithDatafileURLTemplate(headerServer.URL+"/%s.json"),
+		WithDatafileVerifier(NewHMACVerifier(signingKey)))
+	assert.Equal(t, "42", currentRevision(configManager))
+
+	sidecarServer := newSignedDatafileServer(datafile, nil, hmacSignature(datafile))
+	defer sidecarServer.Close()
+	configManager = NewPollingProjectConfigManager("signature_sidecar", requester, WithDatafileURLTemplate(sidecarServer.URL+"/%s.json"),
+		WithDatafileVerifier(NewHMACVerifier(signingKey)))
+	assert.Equal(t, "42", currentRevision(configManager))
+}
+
+func TestSyncConfigRejectsUnsignedAndTamperedDatafiles(t *testing.T) {
+	datafile := `{"revision":"42","version": "4"}`
+	requester := WithRequester(utils.NewHTTPRequester(logging.GetLogger("", "HTTPRequester"), utils.Retries(1)))
+
+	var rejections []notification.DatafileRejectedNotification
+	_, err := registry.GetNotificationCenter("signature_rejected").AddHandler(notification.DatafileRejected, func(payload interface{}) {
+		rejections = append(rejections, payload.(notification.DatafileRejectedNotification))
+	})
+	assert.NoError(t, err)
+
+	unsignedServer := newSignedDatafileServer(datafile, nil, nil)
+	defer unsignedServer.Close()
+	configManager := NewPollingProjectConfigManager("signature_rejected", requester, WithDatafileURLTemplate(unsignedServer.URL+"/%s.json"),
+		WithDatafileVerifier(NewHMACVerifier(signingKey)))
+	_, err = configManager.GetConfig()
+	assert.Equal(t, ErrMissingSignature, err)
+
+	tamperedServer := newSignedDatafileServer(datafile, hmacSignature(`{"revision":"41","version": "4"}`), nil)
+	defer tamperedServer.Close()
+	configManager = NewPollingProjectConfigManager("signature_rejected", requester, WithDatafileURLTemplate(tamperedServer.URL+"/%s.json"),
+		WithDatafileVerifier(NewHMACVerifier(signingKey)))
+	_, err = configManager.GetConfig()
+	assert.Equal(t, ErrInvalidSignature, err)
+
+	if assert.Len(t, rejections, 2) {
+		assert.Equal(t, unsignedServer.URL+"/signature_rejected.json", rejections[0].Source)
+		assert.Equal(t, ErrMissingSignature.Error(), rejections[0].Reason)
+		assert.Equal(t, ErrInvalidSignature.Error(), rejections[1].Reason)
+	}
+}
+
+func TestFileManagerVerifiesSidecarSignature(t *testing.T) {
+	path, cleanup := tempDatafilePath(t)
+	defer cleanup()
+	datafile := `{"revision":"42","version": "4"}`
+	modTime := time.Now().Add(-time.Minute)
+	writeDatafile(t, path, datafile, modTime)
+
+	var rejections []notification.DatafileRejectedNotification
+	_, err := registry.GetNotificationCenter("signature_file").AddHandler(notification.DatafileRejected, func(payload interface{}) {
+		rejections = append(rejections, payload.(notification.DatafileRejectedNotification))
+	})
+	assert.NoError(t, err)
+
+	configManager := NewFileProjectConfigManager("signature_file", path, WithFileDatafileVerifier(NewHMACVerifier(signingKey)))
+	_, err = configManager.GetConfig()
+	assert.Equal(t, ErrMissingSignature, err)
+
+	// rejection is reported once per change
+	configManager.SyncConfig()
+	assert.Len(t, rejections, 1)
+
+	assert.NoError(t, ioutil.WriteFile(path+DatafileSignatureSuffix, []byte(hex.EncodeToString(hmacSignature(datafile))), 0600))
+	configManager.SyncConfig()
+	assert.Equal(t, "42", currentRevision(configManager))
+	assert.Len(t, rejections, 1)
+}
+
+func TestCachedDatafileSignatureIsVerified(t *testing.T) {
+	cachePath, cleanup := tempDatafilePath(t)
+	defer cleanup()
+	datafile := `{"revision":"42", "version": "4"}`
+	verifier := WithDatafileVerifier(NewHMACVerifier(signingKey))
+
+	server := newSignedDatafileServer(datafile, hmacSignature(datafile), nil)
+	defer server.Close()
+	configManager := NewPollingProjectConfigManager("signature_cache", WithDatafileURLTemplate(server.URL+"/%s.json"), verifier,
+		WithDatafileCache(cachePath))
+	assert.Equal(t, "42", currentRevision(configManager))
+
+	// the cached datafile is verified with the signature saved next to it
+	configManager = NewAsyncPollingProjectConfigManager("signature_cache", verifier, WithDatafileCache(cachePath))
+	assert.Equal(t, "42", currentRevision(configManager))
+
+	cachedDatafile, err := NewDatafileCache(cachePath).Load()
+	assert.NoError(t, err)
+	cachedDatafile.SignedDatafile = []byte(`{"revision":"43", "version": "4"}`)
+	tampered, err := json.Marshal(cachedDatafile)
+	assert.NoError(t, err)
+	assert.NoError(t, ioutil.WriteFile(cachePath, tampered, 0600))
+	configManager = NewAsyncPollingProjectConfigManager("signature_cache", verifier, WithDatafileCache(cachePath))
+	_, err = configManager.GetConfig()
+	assert.Equal(t, ErrInvalidSignature, err)
+
+	assert.NoError(t, NewDatafileCache(cachePath).Save([]byte(datafile), "42", time.Now()))
+	configManager = NewAsyncPollingProjectConfigManager("signature_cache", verifier, WithDatafileCache(cachePath))
+	_, err = configManager.GetConfig()
+	assert.Equal(t, ErrMissingSignature, err)
+}
+
+func TestInitialDatafileSignatureIsVerified(t *testing.T) {
+	datafile := `{"revision":"42","version": "4"}`
+	verifier := WithDatafileVerifier(NewHMACVerifier(signingKey))
+
+	configManager := NewAsyncPollingProjectConfigManager("signature_initial", verifier, WithInitialDatafile([]byte(datafile)))
+	_, err := configManager.GetConfig()
+	assert.Equal(t, ErrMissingSignature, err)
+
+	configManager = NewAsyncPollingProjectConfigManager("signature_initial", verifier, WithInitialDatafile([]byte(datafile)),
+		WithInitialDatafileSignature([]byte(hex.EncodeToString(hmacSignature(datafile)))))
+	assert.Equal(t, "42", currentRevision(configManager))
+}
+
+func TestInitialDatafileRejectionHandlerCanReadConfig(t *testing.T) {
+	datafile := `{"revision":"42","version": "4"}`
+	var configManager *PollingProjectConfigManager
+	handled := make(chan error, 1)
+	_, err := registry.GetNotificationCenter("signature_initial_handler").AddHandler(notification.DatafileRejected, func(payload interface{}) {
+		_, err := configManager.GetConfig()
+		handled <- err
+	})
+	assert.NoError(t, err)
+
+	configManager = NewAsyncPollingProjectConfigManager("signature_initial_handler", WithDatafileVerifier(NewHMACVerifier(signingKey)))
+	go configManager.setInitialDatafile([]byte(datafile), nil, time.Now(), "initial datafile")
+
+	select {
+	case err := <-handled:
+		assert.Equal(t, ErrMissingSignature, err)
+	case <-time.After(time.Second):
+		t.Fatal("the rejection handler is blocked on the config lock")
+	}
+}
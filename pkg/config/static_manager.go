@@ -21,9 +21,12 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/WolffunService/experiment/pkg/config/datafileprojectconfig"
+	"github.com/WolffunService/experiment/pkg/config/datafileyaml"
 	"github.com/WolffunService/experiment/pkg/logging"
+	"github.com/WolffunService/experiment/pkg/metrics"
 	"github.com/WolffunService/experiment/pkg/notification"
 	"github.com/WolffunService/experiment/pkg/utils"
 )
@@ -44,7 +47,7 @@ func NewStaticProjectConfigManagerWithOptions(sdkKey string, configMangerOptions
 	if sdkKey != "" {
 		staticProjectConfigManager.SyncConfig()
 	} else if len(staticProjectConfigManager.initDatafile) > 0 {
-		staticProjectConfigManager.setInitialDatafile(staticProjectConfigManager.initDatafile)
+		staticProjectConfigManager.setInitialDatafile(staticProjectConfigManager.initDatafile, staticProjectConfigManager.initSignature, time.Time{}, InitialDatafileSourceName)
 	}
 	projectConfig, err := staticProjectConfigManager.GetConfig()
 	if err != nil {
@@ -66,6 +69,16 @@ func NewStaticProjectConfigManager(config ProjectConfig, logger logging.Optimize
 	}
 }
 
+// NewStaticProjectConfigManagerFromYAML returns new instance of StaticProjectConfigManager for a document in the YAML
+// authoring format of the datafileyaml package, the error lists the problems found in the document with their line
+func NewStaticProjectConfigManagerFromYAML(sdkKey string, document []byte) (*StaticProjectConfigManager, error) {
+	datafile, err := datafileyaml.Convert(document)
+	if err != nil {
+		return nil, err
+	}
+	return NewStaticProjectConfigManagerFromPayload(datafile, logging.GetLogger(sdkKey, "StaticProjectConfigManager"))
+}
+
 /********************* Old Constructors not used in go-sdk, kept for backward compatibility **********/
 
 // NewStaticProjectConfigManagerFromURL returns new instance of StaticProjectConfigManager for URL
@@ -86,7 +99,13 @@ func NewStaticProjectConfigManagerFromURL(sdkKey string) (*StaticProjectConfigMa
 }
 
 // NewStaticProjectConfigManagerFromPayload returns new instance of StaticProjectConfigManager for payload
+// Compressed payloads are decompressed without reporting their sizes, use NewStaticProjectConfigManagerWithOptions with
+// WithInitialDatafile and WithMetricsRegistry to report them
 func NewStaticProjectConfigManagerFromPayload(payload []byte, logger logging.OptimizelyLogProducer) (*StaticProjectConfigManager, error) {
+	payload, err := decompressDatafile(payload, metrics.NewNoopRegistry())
+	if err != nil {
+		return nil, err
+	}
 	projectConfig, err := datafileprojectconfig.NewDatafileProjectConfig(payload, logger)
 
 	if err != nil {
@@ -105,6 +124,13 @@ func (cm *StaticProjectConfigManager) GetConfig() (ProjectConfig, error) {
 	return cm.projectConfig, nil
 }
 
+// Ready returns a closed channel, the static manager always has its config
+func (cm *StaticProjectConfigManager) Ready() <-chan struct{} {
+	ready := make(chan struct{})
+	close(ready)
+	return ready
+}
+
 // GetOptimizelyConfig returns the optimizely project config
 func (cm *StaticProjectConfigManager) GetOptimizelyConfig() *OptimizelyConfig {
 	cm.configLock.Lock()
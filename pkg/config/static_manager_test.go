@@ -46,6 +46,19 @@ func TestNewStaticProjectConfigManagerFromPayload(t *testing.T) {
 	assert.NotNil(t, actual)
 }
 
+func TestNewStaticProjectConfigManagerFromYAML(t *testing.T) {
+	configManager, err := NewStaticProjectConfigManagerFromYAML("", []byte("revision: 42\nflags:\n  checkout: {}\n"))
+	assert.NoError(t, err)
+	actual, _ := configManager.GetConfig()
+	assert.Equal(t, "42", actual.GetRevision())
+	_, err = actual.GetFeatureByKey("checkout")
+	assert.NoError(t, err)
+
+	configManager, err = NewStaticProjectConfigManagerFromYAML("", []byte("revision: 42\nflag: {}\n"))
+	assert.Nil(t, configManager)
+	assert.EqualError(t, err, "line 2: field flag not found in type datafileyaml.Document")
+}
+
 func TestStaticGetOptimizelyConfig(t *testing.T) {
 
 	mockDatafile := []byte(`{"accountId":"42","projectId":"123","version":"4"}`)
@@ -0,0 +1,275 @@
+/****************************************************************************
+ * Copyright 2026, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package config //
+package config
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/WolffunService/experiment/pkg/logging"
+)
+
+// DefaultStreamReconnectDelay sets default delay between attempts to reconnect to the stream
+const DefaultStreamReconnectDelay = 5 * time.Second
+
+// DefaultStreamIdleTimeout sets default time without any data, including keep-alive comments, after which the stream is considered dropped
+const DefaultStreamIdleTimeout = 2 * time.Minute
+
+// StreamingProjectConfigManager maintains a dynamic copy of the project config by subscribing to a Server-Sent Events
+// endpoint which announces new datafile revisions. Every announced revision that differs from the current one is
+// fetched and applied the same way the PollingProjectConfigManager does it. While the stream is down the manager falls
+// back to polling and it switches back to the stream as soon as it reconnects.
+type StreamingProjectConfigManager struct {
+	*PollingProjectConfigManager
+	streamURL      string
+	client         *http.Client
+	reconnectDelay time.Duration
+	idleTimeout    time.Duration
+	pollingOptions []OptionFunc
+	logger         logging.OptimizelyLogProducer
+
+	streamLock  sync.RWMutex
+	connected   bool
+	lastEventID string
+}
+
+// StreamingOptionFunc is used to provide custom configuration to the StreamingProjectConfigManager.
+type StreamingOptionFunc func(*StreamingProjectConfigManager)
+
+// WithStreamHTTPClient is an optional function, sets the http client used for the stream connection
+func WithStreamHTTPClient(client *http.Client) StreamingOptionFunc {
+	return func(s *StreamingProjectConfigManager) {
+		s.client = client
+	}
+}
+
+// WithStreamReconnectDelay is an optional function, sets a passed delay between attempts to reconnect to the stream
+func WithStreamReconnectDelay(delay time.Duration) StreamingOptionFunc {
+	return func(s *StreamingProjectConfigManager) {
+		s.reconnectDelay = delay
+	}
+}
+
+// WithStreamIdleTimeout is an optional function, sets a passed idle timeout of the stream, zero disables it
+func WithStreamIdleTimeout(timeout time.Duration) StreamingOptionFunc {
+	return func(s *StreamingProjectConfigManager) {
+		s.idleTimeout = timeout
+	}
+}
+
+// WithPollingOptions is an optional function, sets the options of the underlying polling manager which fetches the
+// datafile and polls while the stream is down
+func WithPollingOptions(options ...OptionFunc) StreamingOptionFunc {
+	return func(s *StreamingProjectConfigManager) {
+		s.pollingOptions = append(s.pollingOptions, options...)
+	}
+}
+
+// NewStreamingProjectConfigManager returns an instance of the streaming config manager subscribed to the given stream URL
+func NewStreamingProjectConfigManager(sdkKey, streamURL string, streamingOptions ...StreamingOptionFunc) *StreamingProjectConfigManager {
+	streamingProjectConfigManager := &StreamingProjectConfigManager{
+		streamURL:      streamURL,
+		client:         &http.Client{},
+		reconnectDelay: DefaultStreamReconnectDelay,
+		idleTimeout:    DefaultStreamIdleTimeout,
+		logger:         logging.GetLogger(sdkKey, "StreamingProjectConfigManager"),
+	}
+
+	for _, opt := range streamingOptions {
+		opt(streamingProjectConfigManager)
+	}
+
+	streamingProjectConfigManager.PollingProjectConfigManager = NewPollingProjectConfigManager(sdkKey, streamingProjectConfigManager.pollingOptions...)
+	return streamingProjectConfigManager
+}
+
+// IsConnected returns true while the stream is connected
+func (cm *StreamingProjectConfigManager) IsConnected() bool {
+	cm.streamLock.RLock()
+	defer cm.streamLock.RUnlock()
+	return cm.connected
+}
+
+// Start subscribes to the stream and keeps reconnecting until the context is done, polling while disconnected
+func (cm *StreamingProjectConfigManager) Start(ctx context.Context) {
+	cm.logger.Debug("Streaming Config Manager Initiated")
+
+	var pollingWg sync.WaitGroup
+	var stopPolling context.CancelFunc
+	startPolling := func() {
+		if stopPolling != nil {
+			return
+		}
+		var pollingCtx context.Context
+		pollingCtx, stopPolling = context.WithCancel(ctx)
+		pollingWg.Add(1)
+		go func() {
+			defer pollingWg.Done()
+			cm.PollingProjectConfigManager.Start(pollingCtx)
+		}()
+	}
+	onConnected := func() {
+		if stopPolling != nil {
+			stopPolling()
+			pollingWg.Wait()
+			stopPolling = nil
+		}
+		// catch up on revisions announced while disconnected
+		cm.SyncConfig()
+	}
+	defer func() {
+		if stopPolling != nil {
+			stopPolling()
+		}
+		pollingWg.Wait()
+		cm.logger.Debug("Streaming Config Manager Stopped")
+	}()
+
+	for {
+		err := cm.subscribe(ctx, onConnected)
+		if ctx.Err() != nil {
+			return
+		}
+		cm.logger.Warning(fmt.Sprintf("Datafile stream dropped, falling back to polling: %v", err))
+		startPolling()
+
+		t := time.NewTimer(cm.reconnectDelay)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return
+		}
+	}
+}
+
+// subscribe connects to the stream and handles its events until it ends
+func (cm *StreamingProjectConfigManager) subscribe(ctx context.Context, onConnected func()) error {
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	request, err := http.NewRequest(http.MethodGet, cm.streamURL, nil)
+	if err != nil {
+		return err
+	}
+	request = request.WithContext(streamCtx)
+	request.Header.Set("Accept", "text/event-stream")
+	request.Header.Set("Cache-Control", "no-cache")
+	if cm.datafileAccessToken != "" {
+		request.Header.Set("Authorization", "Bearer "+cm.datafileAccessToken)
+	}
+	cm.streamLock.RLock()
+	if cm.lastEventID != "" {
+		request.Header.Set("Last-Event-ID", cm.lastEventID)
+	}
+	cm.streamLock.RUnlock()
+
+	response, err := cm.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected stream status code: %d", response.StatusCode)
+	}
+
+	cm.setConnected(true)
+	defer cm.setConnected(false)
+	cm.logger.Debug(fmt.Sprintf("Subscribed to datafile stream %s", cm.streamURL))
+	onConnected()
+
+	var idleTimer *time.Timer
+	if cm.idleTimeout > 0 {
+		idleTimer = time.AfterFunc(cm.idleTimeout, cancel)
+		defer idleTimer.Stop()
+	}
+
+	var data []string
+	scanner := bufio.NewScanner(response.Body)
+	for scanner.Scan() {
+		if idleTimer != nil {
+			idleTimer.Reset(cm.idleTimeout)
+		}
+		line := scanner.Text()
+		field, value := parseStreamLine(line)
+		switch field {
+		case "":
+			// a blank line dispatches the event
+			if len(data) > 0 {
+				cm.handleEvent(strings.Join(data, "\n"))
+			}
+			data = nil
+		case "id":
+			cm.streamLock.Lock()
+			cm.lastEventID = value
+			cm.streamLock.Unlock()
+		case "data":
+			data = append(data, value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return fmt.Errorf("stream closed by server")
+}
+
+// handleEvent fetches the datafile if the event announces a revision other than the current one. The event data is
+// either a JSON object with a "revision" field or the revision itself.
+func (cm *StreamingProjectConfigManager) handleEvent(data string) {
+	revision := strings.TrimSpace(data)
+	announcement := struct {
+		Revision string `json:"revision"`
+	}{}
+	if err := json.Unmarshal([]byte(data), &announcement); err == nil {
+		revision = announcement.Revision
+	}
+
+	if projectConfig, err := cm.GetConfig(); err == nil && projectConfig != nil && projectConfig.GetRevision() == revision {
+		cm.logger.Debug(fmt.Sprintf("Datafile revision %s announced by stream is already set", revision))
+		return
+	}
+	cm.logger.Debug(fmt.Sprintf("Datafile revision %s announced by stream, fetching datafile", revision))
+	cm.SyncConfig()
+}
+
+func (cm *StreamingProjectConfigManager) setConnected(connected bool) {
+	cm.streamLock.Lock()
+	cm.connected = connected
+	cm.streamLock.Unlock()
+}
+
+// parseStreamLine splits a Server-Sent Events line into its field and value, comments yield the "comment" field
+func parseStreamLine(line string) (field, value string) {
+	if line == "" {
+		return "", ""
+	}
+	if strings.HasPrefix(line, ":") {
+		return "comment", ""
+	}
+	parts := strings.SplitN(line, ":", 2)
+	if len(parts) == 1 {
+		return parts[0], ""
+	}
+	return parts[0], strings.TrimPrefix(parts[1], " ")
+}
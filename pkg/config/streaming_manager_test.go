@@ -0,0 +1,221 @@
+/****************************************************************************
+ * Copyright 2026, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package config //
+package config
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/WolffunService/experiment/pkg/notification"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testDatafileServer struct {
+	lock     sync.Mutex
+	revision string
+	*httptest.Server
+}
+
+func newTestDatafileServer(revision string) *testDatafileServer {
+	s := &testDatafileServer{revision: revision}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		fmt.Fprintf(w, `{"revision":"%s","version": "4"}`, s.revision)
+	}))
+	return s
+}
+
+func (s *testDatafileServer) setRevision(revision string) {
+	s.lock.Lock()
+	s.revision = revision
+	s.lock.Unlock()
+}
+
+func newTestStreamServer(events chan string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, ": connected\n\n")
+		w.(http.Flusher).Flush()
+		for {
+			select {
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				fmt.Fprint(w, event)
+				w.(http.Flusher).Flush()
+			case <-r.Context().Done():
+				return
+			}
+		}
+	}))
+}
+
+func currentRevision(cm ProjectConfigManager) string {
+	projectConfig, err := cm.GetConfig()
+	if err != nil {
+		return ""
+	}
+	return projectConfig.GetRevision()
+}
+
+func TestStreamingManagerFetchesAnnouncedRevision(t *testing.T) {
+	datafileServer := newTestDatafileServer("42")
+	defer datafileServer.Close()
+	events := make(chan string)
+	streamServer := newTestStreamServer(events)
+	defer streamServer.Close()
+
+	configManager := NewStreamingProjectConfigManager("stream_announce", streamServer.URL,
+		WithPollingOptions(WithDatafileURLTemplate(datafileServer.URL+"/%s"), WithPollingInterval(time.Hour)))
+	assert.Equal(t, "42", currentRevision(configManager))
+
+	var revisions []string
+	var lock sync.Mutex
+	configManager.OnProjectConfigUpdate(func(n notification.ProjectConfigUpdateNotification) {
+		lock.Lock()
+		revisions = append(revisions, n.Revision)
+		lock.Unlock()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go configManager.Start(ctx)
+	assert.Eventually(t, configManager.IsConnected, time.Second, 5*time.Millisecond)
+
+	datafileServer.setRevision("43")
+	events <- "id: 1\ndata: {\"revision\":\"43\"}\n\n"
+	assert.Eventually(t, func() bool { return currentRevision(configManager) == "43" }, time.Second, 5*time.Millisecond)
+
+	datafileServer.setRevision("44")
+	events <- "data: 44\n\n"
+	assert.Eventually(t, func() bool { return currentRevision(configManager) == "44" }, time.Second, 5*time.Millisecond)
+
+	lock.Lock()
+	assert.Equal(t, []string{"43", "44"}, revisions)
+	lock.Unlock()
+}
+
+func TestStreamingManagerFallsBackToPollingWhenStreamDrops(t *testing.T) {
+	datafileServer := newTestDatafileServer("42")
+	defer datafileServer.Close()
+	streamServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer streamServer.Close()
+
+	configManager := NewStreamingProjectConfigManager("stream_fallback", streamServer.URL,
+		WithStreamReconnectDelay(time.Hour),
+		WithPollingOptions(WithDatafileURLTemplate(datafileServer.URL+"/%s"), WithPollingInterval(10*time.Millisecond)))
+	assert.Equal(t, "42", currentRevision(configManager))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		configManager.Start(ctx)
+		close(stopped)
+	}()
+
+	datafileServer.setRevision("43")
+	assert.Eventually(t, func() bool { return currentRevision(configManager) == "43" }, time.Second, 5*time.Millisecond)
+	assert.False(t, configManager.IsConnected())
+
+	cancel()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Error("streaming manager did not stop")
+	}
+}
+
+func TestStreamingManagerReconnectsAndCatchesUp(t *testing.T) {
+	datafileServer := newTestDatafileServer("42")
+	defer datafileServer.Close()
+	events := make(chan string)
+	streamServer := newTestStreamServer(events)
+	defer streamServer.Close()
+
+	configManager := NewStreamingProjectConfigManager("stream_reconnect", streamServer.URL,
+		WithStreamReconnectDelay(10*time.Millisecond),
+		WithPollingOptions(WithDatafileURLTemplate(datafileServer.URL+"/%s"), WithPollingInterval(time.Hour)))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go configManager.Start(ctx)
+	assert.Eventually(t, configManager.IsConnected, time.Second, 5*time.Millisecond)
+
+	// the server drops the stream, the revision changes while disconnected
+	datafileServer.setRevision("43")
+	events <- "id: 7\n\n"
+	close(events)
+	streamServer.CloseClientConnections()
+
+	assert.Eventually(t, func() bool { return currentRevision(configManager) == "43" }, time.Second, 5*time.Millisecond)
+}
+
+func TestStreamingManagerReconnectsWhenIdle(t *testing.T) {
+	datafileServer := newTestDatafileServer("42")
+	defer datafileServer.Close()
+	var lock sync.Mutex
+	connections := 0
+	streamServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		connections++
+		lock.Unlock()
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer streamServer.Close()
+
+	configManager := NewStreamingProjectConfigManager("stream_idle", streamServer.URL,
+		WithStreamIdleTimeout(20*time.Millisecond), WithStreamReconnectDelay(time.Millisecond),
+		WithPollingOptions(WithDatafileURLTemplate(datafileServer.URL+"/%s"), WithPollingInterval(time.Hour)))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go configManager.Start(ctx)
+
+	assert.Eventually(t, func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return connections >= 2
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestParseStreamLine(t *testing.T) {
+	field, value := parseStreamLine("data: {\"revision\":\"1\"}")
+	assert.Equal(t, "data", field)
+	assert.Equal(t, "{\"revision\":\"1\"}", value)
+
+	field, value = parseStreamLine("id:5")
+	assert.Equal(t, "id", field)
+	assert.Equal(t, "5", value)
+
+	field, _ = parseStreamLine(": keep-alive")
+	assert.Equal(t, "comment", field)
+
+	field, _ = parseStreamLine("")
+	assert.Equal(t, "", field)
+}
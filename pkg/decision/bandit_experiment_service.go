@@ -0,0 +1,312 @@
+/****************************************************************************
+ * Copyright 2026, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package decision //
+package decision
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/WolffunService/experiment/pkg/config"
+	"github.com/WolffunService/experiment/pkg/decide"
+	pkgReasons "github.com/WolffunService/experiment/pkg/decision/reasons"
+	"github.com/WolffunService/experiment/pkg/entities"
+	"github.com/WolffunService/experiment/pkg/logging"
+)
+
+// BanditArm holds the exposures and conversions observed for a variation of a bandit experiment
+type BanditArm struct {
+	Exposures   int
+	Conversions int
+}
+
+// BanditStore keeps the state bandit experiments allocate traffic by. Users are identified by their bucketing ID.
+type BanditStore interface {
+	// GetArms returns the arms of the experiment keyed by variation ID
+	GetArms(experimentID string) map[string]BanditArm
+	// GetAssignment returns the variation ID the user was allocated to
+	GetAssignment(experimentID, bucketingID string) (string, bool)
+	// Assign allocates the user to the variation, without counting an exposure
+	Assign(experimentID, variationID, bucketingID string)
+	// RecordExposure counts an exposure of the variation the user was allocated to, once per user
+	RecordExposure(experimentID, bucketingID string)
+	// RecordConversion counts a conversion for the variation the user was exposed to, once per user
+	RecordConversion(experimentID, bucketingID string)
+}
+
+type banditAssignment struct {
+	variationID string
+	exposed     bool
+	converted   bool
+}
+
+// MapBanditStore is a map-based implementation of BanditStore that is safe to use concurrently.
+// It is not bounded: every user allocated to a bandit experiment is kept in memory for the lifetime of the store.
+// Services deciding for many distinct users should implement BanditStore on top of a shared store that expires
+// assignments instead.
+type MapBanditStore struct {
+	arms        map[string]map[string]BanditArm
+	assignments map[string]map[string]*banditAssignment
+	mutex       sync.RWMutex
+}
+
+// NewMapBanditStore returns a new MapBanditStore
+func NewMapBanditStore() *MapBanditStore {
+	return &MapBanditStore{
+		arms:        make(map[string]map[string]BanditArm),
+		assignments: make(map[string]map[string]*banditAssignment),
+	}
+}
+
+// GetArms returns a copy of the arms of the experiment keyed by variation ID
+func (m *MapBanditStore) GetArms(experimentID string) map[string]BanditArm {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	arms := make(map[string]BanditArm, len(m.arms[experimentID]))
+	for variationID, arm := range m.arms[experimentID] {
+		arms[variationID] = arm
+	}
+	return arms
+}
+
+// GetAssignment returns the variation ID the user was allocated to
+func (m *MapBanditStore) GetAssignment(experimentID, bucketingID string) (string, bool) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	if assignment, ok := m.assignments[experimentID][bucketingID]; ok {
+		return assignment.variationID, true
+	}
+	return "", false
+}
+
+// Assign allocates the user to the variation, without counting an exposure
+func (m *MapBanditStore) Assign(experimentID, variationID, bucketingID string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if _, ok := m.assignments[experimentID]; !ok {
+		m.arms[experimentID] = make(map[string]BanditArm)
+		m.assignments[experimentID] = make(map[string]*banditAssignment)
+	}
+	m.assignments[experimentID][bucketingID] = &banditAssignment{variationID: variationID}
+}
+
+// RecordExposure counts an exposure of the variation the user was allocated to, once per user
+func (m *MapBanditStore) RecordExposure(experimentID, bucketingID string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	assignment, ok := m.assignments[experimentID][bucketingID]
+	if !ok || assignment.exposed {
+		return
+	}
+	assignment.exposed = true
+	arm := m.arms[experimentID][assignment.variationID]
+	arm.Exposures++
+	m.arms[experimentID][assignment.variationID] = arm
+}
+
+// RecordConversion counts a conversion for the variation the user was exposed to, once per user
+func (m *MapBanditStore) RecordConversion(experimentID, bucketingID string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	assignment, ok := m.assignments[experimentID][bucketingID]
+	if !ok || !assignment.exposed || assignment.converted {
+		return
+	}
+	assignment.converted = true
+	arm := m.arms[experimentID][assignment.variationID]
+	arm.Conversions++
+	m.arms[experimentID][assignment.variationID] = arm
+}
+
+// TrackBanditConversion records a conversion of the tracked event for every bandit experiment measuring it
+func TrackBanditConversion(store BanditStore, projectConfig config.ProjectConfig, eventKey, bucketingID string) {
+	var eventExperimentIDs []string
+	if event, err := projectConfig.GetEventByKey(eventKey); err == nil {
+		eventExperimentIDs = event.ExperimentIds
+	}
+	for _, experiment := range projectConfig.GetExperimentList() {
+		if experiment.Bandit == nil {
+			continue
+		}
+		if experiment.Bandit.EventKey == eventKey || (experiment.Bandit.EventKey == "" && containsString(eventExperimentIDs, experiment.ID)) {
+			store.RecordConversion(experiment.ID, bucketingID)
+		}
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// BanditOptionFunc is used to assign optional configuration options to the BanditExperimentService
+type BanditOptionFunc func(*BanditExperimentService)
+
+// WithBanditRandomSource sets the source of randomness the bandit samples with
+func WithBanditRandomSource(source rand.Source) BanditOptionFunc {
+	return func(s *BanditExperimentService) {
+		s.random = rand.New(source)
+	}
+}
+
+// BanditExperimentService allocates users of experiments carrying bandit metadata to a variation picked by Thompson
+// sampling or epsilon-greedy from the arms in the BanditStore. The wrapped experiment service decides whether the
+// user is part of the experiment at all, and makes the decision for any other experiment. Allocations are not counted
+// as exposures, the client records an exposure when it sends the impression event of the decision.
+type BanditExperimentService struct {
+	experimentService ExperimentService
+	store             BanditStore
+	random            *rand.Rand
+	randomMutex       sync.Mutex
+	logger            logging.OptimizelyLogProducer
+}
+
+// NewBanditExperimentService returns a new instance of the BanditExperimentService
+func NewBanditExperimentService(store BanditStore, experimentService ExperimentService, logger logging.OptimizelyLogProducer, options ...BanditOptionFunc) *BanditExperimentService {
+	banditExperimentService := &BanditExperimentService{
+		experimentService: experimentService,
+		store:             store,
+		random:            rand.New(rand.NewSource(time.Now().UnixNano())),
+		logger:            logger,
+	}
+	for _, opt := range options {
+		opt(banditExperimentService)
+	}
+	return banditExperimentService
+}
+
+// GetDecision returns the decision with the variation the bandit allocates the user to
+func (s *BanditExperimentService) GetDecision(decisionContext ExperimentDecisionContext, userContext entities.UserContext, options *decide.Options) (ExperimentDecision, decide.DecisionReasons, error) {
+	experimentDecision, reasons, err := s.experimentService.GetDecision(decisionContext, userContext, options)
+	experiment := decisionContext.Experiment
+	if experiment == nil || experiment.Bandit == nil || experimentDecision.Variation == nil || err != nil {
+		return experimentDecision, reasons, err
+	}
+
+	// the wrapped service already reports invalid bucketing IDs
+	bucketingID, _ := userContext.GetBucketingID()
+	if variationID, ok := s.store.GetAssignment(experiment.ID, bucketingID); ok {
+		if variation, ok := experiment.Variations[variationID]; ok {
+			experimentDecision.Variation = &variation
+			experimentDecision.Reason = pkgReasons.BanditAllocatedVariation
+			return experimentDecision, reasons, nil
+		}
+	}
+
+	variationID := s.pickVariation(*experiment)
+	if variationID == "" {
+		s.logger.Warning(fmt.Sprintf(`Unknown bandit algorithm "%s" for experiment "%s", using its traffic allocation`, experiment.Bandit.Algorithm, experiment.Key))
+		return experimentDecision, reasons, nil
+	}
+	variation := experiment.Variations[variationID]
+	s.store.Assign(experiment.ID, variationID, bucketingID)
+	logMessage := reasons.AddInfo(`User "%s" was allocated to variation "%s" of bandit experiment "%s".`, userContext.ID, variation.Key, experiment.Key)
+	s.logger.Debug(logMessage)
+	experimentDecision.Variation = &variation
+	experimentDecision.Reason = pkgReasons.BanditAllocatedVariation
+	return experimentDecision, reasons, nil
+}
+
+func (s *BanditExperimentService) pickVariation(experiment entities.Experiment) string {
+	variationIDs := make([]string, 0, len(experiment.Variations))
+	for variationID := range experiment.Variations {
+		variationIDs = append(variationIDs, variationID)
+	}
+	if len(variationIDs) == 0 {
+		return ""
+	}
+	sort.Strings(variationIDs)
+	arms := s.store.GetArms(experiment.ID)
+
+	s.randomMutex.Lock()
+	defer s.randomMutex.Unlock()
+	switch experiment.Bandit.Algorithm {
+	case entities.BanditThompsonSampling:
+		return s.thompsonSampling(variationIDs, arms)
+	case entities.BanditEpsilonGreedy:
+		return s.epsilonGreedy(variationIDs, arms, experiment.Bandit.Epsilon)
+	default:
+		return ""
+	}
+}
+
+// thompsonSampling draws a conversion rate from the Beta posterior of every arm and picks the highest
+func (s *BanditExperimentService) thompsonSampling(variationIDs []string, arms map[string]BanditArm) string {
+	best, bestDraw := "", -1.0
+	for _, variationID := range variationIDs {
+		arm := arms[variationID]
+		failures := arm.Exposures - arm.Conversions
+		if failures < 0 {
+			failures = 0
+		}
+		if draw := s.sampleBeta(float64(arm.Conversions+1), float64(failures+1)); draw > bestDraw {
+			best, bestDraw = variationID, draw
+		}
+	}
+	return best
+}
+
+// epsilonGreedy explores a random arm with probability epsilon and otherwise exploits the best converting one,
+// arms without exposures counting as the best
+func (s *BanditExperimentService) epsilonGreedy(variationIDs []string, arms map[string]BanditArm, epsilon float64) string {
+	if s.random.Float64() < epsilon {
+		return variationIDs[s.random.Intn(len(variationIDs))]
+	}
+	best, bestRate := "", -1.0
+	for _, variationID := range variationIDs {
+		arm := arms[variationID]
+		rate := 1.0
+		if arm.Exposures > 0 {
+			rate = float64(arm.Conversions) / float64(arm.Exposures)
+		}
+		if rate > bestRate {
+			best, bestRate = variationID, rate
+		}
+	}
+	return best
+}
+
+func (s *BanditExperimentService) sampleBeta(alpha, beta float64) float64 {
+	x := s.sampleGamma(alpha)
+	return x / (x + s.sampleGamma(beta))
+}
+
+// sampleGamma draws from Gamma(shape, 1) using the Marsaglia and Tsang method, valid for shape >= 1
+func (s *BanditExperimentService) sampleGamma(shape float64) float64 {
+	d := shape - 1.0/3
+	c := 1 / math.Sqrt(9*d)
+	for {
+		x := s.random.NormFloat64()
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+		u := s.random.Float64()
+		if u < 1-0.0331*x*x*x*x || math.Log(u) < 0.5*x*x+d*(1-v+math.Log(v)) {
+			return d * v
+		}
+	}
+}
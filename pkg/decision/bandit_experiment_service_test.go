@@ -0,0 +1,239 @@
+/****************************************************************************
+ * Copyright 2026, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+package decision
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/WolffunService/experiment/pkg/config/datafilebuilder"
+	"github.com/WolffunService/experiment/pkg/config/datafileprojectconfig"
+	"github.com/WolffunService/experiment/pkg/decide"
+	"github.com/WolffunService/experiment/pkg/decision/reasons"
+	"github.com/WolffunService/experiment/pkg/entities"
+	"github.com/WolffunService/experiment/pkg/logging"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type BanditExperimentServiceTestSuite struct {
+	suite.Suite
+	mockExperimentService *MockExperimentDecisionService
+	store                 *MapBanditStore
+	experiment            entities.Experiment
+	options               *decide.Options
+}
+
+func (s *BanditExperimentServiceTestSuite) SetupTest() {
+	s.mockExperimentService = new(MockExperimentDecisionService)
+	s.store = NewMapBanditStore()
+	s.options = &decide.Options{}
+	s.experiment = entities.Experiment{
+		ID:  "1111",
+		Key: "bandit_experiment",
+		Variations: map[string]entities.Variation{
+			"2222": {ID: "2222", Key: "a"},
+			"2223": {ID: "2223", Key: "b"},
+		},
+		Bandit: &entities.Bandit{Algorithm: entities.BanditThompsonSampling},
+	}
+}
+
+func (s *BanditExperimentServiceTestSuite) newService() *BanditExperimentService {
+	return NewBanditExperimentService(s.store, s.mockExperimentService, logging.GetLogger("", "BanditExperimentService"),
+		WithBanditRandomSource(rand.NewSource(42)))
+}
+
+func (s *BanditExperimentServiceTestSuite) decide(service *BanditExperimentService, userID string) ExperimentDecision {
+	decisionContext := ExperimentDecisionContext{Experiment: &s.experiment}
+	userContext := entities.UserContext{ID: userID}
+	staticDecision := ExperimentDecision{Variation: &testExp1111Var2222, Decision: Decision{Reason: reasons.BucketedIntoVariation}}
+	s.mockExperimentService.On("GetDecision", decisionContext, userContext, s.options).Return(staticDecision, decide.NewDecisionReasons(s.options), nil)
+	decision, _, err := service.GetDecision(decisionContext, userContext, s.options)
+	s.NoError(err)
+	return decision
+}
+
+func (s *BanditExperimentServiceTestSuite) TestGetDecisionDelegatesWithoutBandit() {
+	s.experiment.Bandit = nil
+	decision := s.decide(s.newService(), "user_1")
+	s.Equal(&testExp1111Var2222, decision.Variation)
+	s.Equal(reasons.BucketedIntoVariation, decision.Reason)
+	s.Empty(s.store.GetArms(s.experiment.ID))
+}
+
+func (s *BanditExperimentServiceTestSuite) TestGetDecisionRespectsEligibility() {
+	decisionContext := ExperimentDecisionContext{Experiment: &s.experiment}
+	userContext := entities.UserContext{ID: "user_1"}
+	notBucketed := ExperimentDecision{Decision: Decision{Reason: reasons.FailedAudienceTargeting}}
+	s.mockExperimentService.On("GetDecision", decisionContext, userContext, s.options).Return(notBucketed, decide.NewDecisionReasons(s.options), nil)
+
+	decision, _, err := s.newService().GetDecision(decisionContext, userContext, s.options)
+	s.NoError(err)
+	s.Nil(decision.Variation)
+	s.Empty(s.store.GetArms(s.experiment.ID))
+}
+
+func (s *BanditExperimentServiceTestSuite) TestGetDecisionIsSticky() {
+	service := s.newService()
+	first := s.decide(service, "user_1")
+	s.Equal(reasons.BanditAllocatedVariation, first.Reason)
+	for i := 0; i < 10; i++ {
+		s.Equal(first.Variation, s.decide(service, "user_1").Variation)
+	}
+	// allocations are exposures only once the client sends their impression
+	s.Equal(BanditArm{}, s.store.GetArms(s.experiment.ID)[first.Variation.ID])
+	s.store.RecordExposure(s.experiment.ID, "user_1")
+	s.store.RecordExposure(s.experiment.ID, "user_1")
+	s.Equal(BanditArm{Exposures: 1}, s.store.GetArms(s.experiment.ID)[first.Variation.ID])
+}
+
+func (s *BanditExperimentServiceTestSuite) TestGetDecisionUsesBucketingID() {
+	service := s.newService()
+	decisionContext := ExperimentDecisionContext{Experiment: &s.experiment}
+	userContext := entities.UserContext{ID: "user_1", Attributes: map[string]interface{}{"$opt_bucketing_id": "household_1"}}
+	staticDecision := ExperimentDecision{Variation: &testExp1111Var2222, Decision: Decision{Reason: reasons.BucketedIntoVariation}}
+	s.mockExperimentService.On("GetDecision", decisionContext, userContext, s.options).Return(staticDecision, decide.NewDecisionReasons(s.options), nil)
+
+	decision, _, err := service.GetDecision(decisionContext, userContext, s.options)
+	s.NoError(err)
+	variationID, ok := s.store.GetAssignment(s.experiment.ID, "household_1")
+	s.True(ok)
+	s.Equal(decision.Variation.ID, variationID)
+	_, ok = s.store.GetAssignment(s.experiment.ID, "user_1")
+	s.False(ok)
+}
+
+func (s *BanditExperimentServiceTestSuite) TestThompsonSamplingFavorsBestArm() {
+	for i := 0; i < 100; i++ {
+		s.expose("2222", fmt.Sprintf("seed_a_%d", i))
+		s.expose("2223", fmt.Sprintf("seed_b_%d", i))
+		if i < 50 {
+			s.store.RecordConversion(s.experiment.ID, fmt.Sprintf("seed_a_%d", i))
+		}
+		if i < 5 {
+			s.store.RecordConversion(s.experiment.ID, fmt.Sprintf("seed_b_%d", i))
+		}
+	}
+
+	service := s.newService()
+	allocations := map[string]int{}
+	for i := 0; i < 200; i++ {
+		allocations[s.decide(service, fmt.Sprintf("user_%d", i)).Variation.Key]++
+	}
+	s.True(allocations["a"] > 190, "allocations: %v", allocations)
+	s.Equal(BanditArm{Exposures: 100, Conversions: 50}, s.store.GetArms(s.experiment.ID)["2222"])
+}
+
+func (s *BanditExperimentServiceTestSuite) TestEpsilonGreedy() {
+	s.experiment.Bandit = &entities.Bandit{Algorithm: entities.BanditEpsilonGreedy}
+	service := s.newService()
+
+	// arms without exposures are explored first
+	s.Equal("a", s.decide(service, "user_1").Variation.Key)
+	s.store.RecordExposure(s.experiment.ID, "user_1")
+	s.Equal("b", s.decide(service, "user_2").Variation.Key)
+	s.store.RecordExposure(s.experiment.ID, "user_2")
+
+	// then the best converting arm is exploited
+	s.store.RecordConversion(s.experiment.ID, "user_2")
+	for i := 3; i < 10; i++ {
+		s.Equal("b", s.decide(service, fmt.Sprintf("user_%d", i)).Variation.Key)
+	}
+
+	// and a random one is explored with probability epsilon
+	s.experiment.Bandit.Epsilon = 1
+	allocations := map[string]int{}
+	for i := 10; i < 110; i++ {
+		allocations[s.decide(service, fmt.Sprintf("user_%d", i)).Variation.Key]++
+	}
+	s.True(allocations["a"] > 25 && allocations["b"] > 25, "allocations: %v", allocations)
+}
+
+func (s *BanditExperimentServiceTestSuite) TestUnknownAlgorithmUsesTrafficAllocation() {
+	s.experiment.Bandit = &entities.Bandit{Algorithm: "ucb"}
+	decision := s.decide(s.newService(), "user_1")
+	s.Equal(&testExp1111Var2222, decision.Variation)
+	s.Empty(s.store.GetArms(s.experiment.ID))
+}
+
+func (s *BanditExperimentServiceTestSuite) expose(variationID, bucketingID string) {
+	s.store.Assign(s.experiment.ID, variationID, bucketingID)
+	s.store.RecordExposure(s.experiment.ID, bucketingID)
+}
+
+func TestBanditExperimentServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(BanditExperimentServiceTestSuite))
+}
+
+func TestMapBanditStore(t *testing.T) {
+	store := NewMapBanditStore()
+	_, ok := store.GetAssignment("1111", "user_1")
+	assert.False(t, ok)
+
+	store.Assign("1111", "2222", "user_1")
+	store.Assign("1111", "2223", "user_2")
+	store.Assign("1111", "2223", "user_3")
+	variationID, ok := store.GetAssignment("1111", "user_1")
+	assert.True(t, ok)
+	assert.Equal(t, "2222", variationID)
+
+	// exposures count once per allocated user
+	store.RecordExposure("1111", "user_1")
+	store.RecordExposure("1111", "user_1")
+	store.RecordExposure("1111", "user_2")
+	store.RecordExposure("1111", "user_4")
+
+	// conversions count once per exposed user
+	store.RecordConversion("1111", "user_1")
+	store.RecordConversion("1111", "user_1")
+	store.RecordConversion("1111", "user_3")
+	store.RecordConversion("1111", "user_4")
+	assert.Equal(t, map[string]BanditArm{"2222": {Exposures: 1, Conversions: 1}, "2223": {Exposures: 1}}, store.GetArms("1111"))
+	assert.Empty(t, store.GetArms("1112"))
+}
+
+func TestTrackBanditConversion(t *testing.T) {
+	builder := datafilebuilder.New()
+	builder.Flag("flag_1").
+		Experiment("listed").Bandit(entities.BanditThompsonSampling, 0, "").Variation("a", true).
+		Experiment("metric").Bandit(entities.BanditThompsonSampling, 0, "signup").Variation("a", true).
+		Experiment("static").Variation("a", true)
+	builder.Event("purchase", "listed", "static").Event("signup")
+	projectConfig, err := datafileprojectconfig.NewDatafileProjectConfig(builder.MustBuild(), logging.GetLogger("", "DatafileProjectConfig"))
+	assert.NoError(t, err)
+
+	store := NewMapBanditStore()
+	for _, experimentKey := range []string{"listed", "metric", "static"} {
+		store.Assign(builder.ExperimentID(experimentKey), builder.VariationID(experimentKey, "a"), "user_1")
+		store.RecordExposure(builder.ExperimentID(experimentKey), "user_1")
+	}
+	conversions := func(experimentKey string) int {
+		return store.GetArms(builder.ExperimentID(experimentKey))[builder.VariationID(experimentKey, "a")].Conversions
+	}
+
+	// events convert the bandit experiments they list, unless an experiment names its own conversion event
+	TrackBanditConversion(store, projectConfig, "purchase", "user_1")
+	assert.Equal(t, 1, conversions("listed"))
+	assert.Equal(t, 0, conversions("metric"))
+	assert.Equal(t, 0, conversions("static"))
+	TrackBanditConversion(store, projectConfig, "signup", "user_1")
+	assert.Equal(t, 1, conversions("metric"))
+	TrackBanditConversion(store, projectConfig, "unknown", "user_1")
+}
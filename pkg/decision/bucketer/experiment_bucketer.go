@@ -21,38 +21,110 @@ import (
 	"github.com/WolffunService/experiment/pkg/decision/reasons"
 	"github.com/WolffunService/experiment/pkg/entities"
 	"github.com/WolffunService/experiment/pkg/logging"
+	"math"
 	"strconv"
+	"time"
 )
 
+// rampKeySuffix is appended to the bucketing key of the ramp schedule so that whether a user is let into an
+// experiment is independent of the variation they get
+const rampKeySuffix = "ramp"
+
 // ExperimentBucketer is used to bucket the user into a particular entity in the experiment's traffic alloc range
 type ExperimentBucketer interface {
 	Bucket(bucketingID string, experiment entities.Experiment, group entities.Group) (*entities.Variation, reasons.Reason, error)
 }
 
+// ExperimentBucketerOptionFunc is used to provide optional configuration to the experiment bucketer
+type ExperimentBucketerOptionFunc func(*HashExperimentBucketer)
+
+// WithExperimentSalts sets per-experiment salts, keyed by experiment ID so that renaming an experiment keeps its salt,
+// that are appended to the bucketing key so that experiments sharing the same user IDs don't share bucket positions
+func WithExperimentSalts(salts map[string]string) ExperimentBucketerOptionFunc {
+	return func(b *HashExperimentBucketer) {
+		b.salts = make(map[string]string, len(salts))
+		for experimentID, salt := range salts {
+			b.salts[experimentID] = salt
+		}
+	}
+}
+
+// WithRevisionBucketing sets whether the experiment revision is part of the bucketing key, in which case bumping the
+// revision re-randomizes every user. It is enabled by default and experiments setting BucketByRevision override it.
+func WithRevisionBucketing(enabled bool) ExperimentBucketerOptionFunc {
+	return func(b *HashExperimentBucketer) {
+		b.ignoreRevision = !enabled
+	}
+}
+
+// WithRampClock sets the clock the ramp schedules of experiments are evaluated against, it defaults to time.Now
+func WithRampClock(now func() time.Time) ExperimentBucketerOptionFunc {
+	return func(b *HashExperimentBucketer) {
+		b.now = now
+	}
+}
+
+// HashExperimentBucketer buckets the user using the hash algorithm of the underlying Bucketer
+type HashExperimentBucketer struct {
+	bucketer           Bucketer
+	salts              map[string]string
+	ignoreRevision     bool
+	groupPolicies      map[string]GroupPolicy
+	unknownGroupPolicy string
+	now                func() time.Time
+}
+
 // MurmurhashExperimentBucketer buckets the user using the mmh3 algorightm
-type MurmurhashExperimentBucketer struct {
-	bucketer Bucketer
+type MurmurhashExperimentBucketer = HashExperimentBucketer
+
+// NewExperimentBucketer returns a new instance of the experiment bucketer hashing with the given bucketer
+func NewExperimentBucketer(bucketer Bucketer, options ...ExperimentBucketerOptionFunc) *HashExperimentBucketer {
+	experimentBucketer := &HashExperimentBucketer{bucketer: bucketer, groupPolicies: defaultGroupPolicies(), now: time.Now}
+	for _, opt := range options {
+		opt(experimentBucketer)
+	}
+	return experimentBucketer
 }
 
 // NewMurmurhashExperimentBucketer returns a new instance of the murmurhash experiment bucketer
 func NewMurmurhashExperimentBucketer(logger logging.OptimizelyLogProducer, hashSeed uint32) *MurmurhashExperimentBucketer {
-	return &MurmurhashExperimentBucketer{
-		bucketer: MurmurhashBucketer{hashSeed: hashSeed, logger: logger},
-	}
+	return NewExperimentBucketer(MurmurhashBucketer{hashSeed: hashSeed, logger: logger})
 }
 
 // Bucket buckets the user into the given experiment
-func (b MurmurhashExperimentBucketer) Bucket(bucketingID string, experiment entities.Experiment, group entities.Group) (*entities.Variation, reasons.Reason, error) {
-	if experiment.GroupID != "" && group.Policy == "random" {
-		bucketKey := bucketingID + group.ID
-		bucketedExperimentID := b.bucketer.BucketToEntity(bucketKey, group.TrafficAllocation)
-		if bucketedExperimentID == "" || bucketedExperimentID != experiment.ID {
-			// User is not bucketed into provided experiment in mutex group
-			return nil, reasons.NotBucketedIntoVariation, nil
+func (b HashExperimentBucketer) Bucket(bucketingID string, experiment entities.Experiment, group entities.Group) (*entities.Variation, reasons.Reason, error) {
+	// the group policy decides whether the user may take part in this experiment of the group, mutex groups and
+	// layers both use the random policy
+	if group.Policy != "" {
+		policy, ok := b.groupPolicies[group.Policy]
+		if !ok && b.unknownGroupPolicy != "" {
+			policy, ok = b.groupPolicies[b.unknownGroupPolicy]
+		}
+		if !ok {
+			return nil, reasons.UnknownGroupPolicy, nil
+		}
+		if admitted, reason := policy.Admit(b.bucketer, bucketingID, experiment, group); !admitted {
+			return nil, reason, nil
 		}
 	}
 
-	bucketKey := bucketingID + experiment.ID + strconv.Itoa(experiment.Revision)
+	// users are let in by a hash of their own, so those let in at a lower percentage stay in as the ramp grows
+	if len(experiment.RampSchedule) > 0 {
+		rampRange := []entities.Range{{
+			EntityID:   experiment.ID,
+			EndOfRange: int(math.Round(experiment.RampPercentage(b.now()) * maxTrafficValue / 100)),
+		}}
+		rampKey := bucketingID + experiment.ID + rampKeySuffix + b.salts[experiment.ID]
+		if b.bucketer.BucketToEntity(rampKey, rampRange) == "" {
+			return nil, reasons.NotInRamp, nil
+		}
+	}
+
+	bucketKey := bucketingID + experiment.ID
+	if b.bucketsByRevision(experiment) {
+		bucketKey += strconv.Itoa(experiment.Revision)
+	}
+	bucketKey += b.salts[experiment.ID]
 	bucketedVariationID := b.bucketer.BucketToEntity(bucketKey, experiment.TrafficAllocation)
 	if bucketedVariationID == "" {
 		// User is not bucketed into a variation in the experiment, return nil variation
@@ -65,3 +137,10 @@ func (b MurmurhashExperimentBucketer) Bucket(bucketingID string, experiment enti
 
 	return nil, reasons.BucketedVariationNotFound, nil
 }
+
+func (b HashExperimentBucketer) bucketsByRevision(experiment entities.Experiment) bool {
+	if experiment.BucketByRevision != nil {
+		return *experiment.BucketByRevision
+	}
+	return !b.ignoreRevision
+}
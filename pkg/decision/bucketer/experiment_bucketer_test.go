@@ -2,7 +2,9 @@ package bucketer
 
 import (
 	"github.com/WolffunService/experiment/pkg/logging"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/WolffunService/experiment/pkg/decision/reasons"
 
@@ -57,3 +59,83 @@ func TestBucketExclusionGroups(t *testing.T) {
 	assert.Nil(t, bucketedVariation)
 	assert.Equal(t, reasons.NotBucketedIntoVariation, reason)
 }
+
+func TestBucketWithExperimentSalts(t *testing.T) {
+	trafficAllocation := []entities.Range{{EntityID: "22222", EndOfRange: 5000}, {EntityID: "22223", EndOfRange: 10000}}
+	experiment1 := entities.Experiment{ID: "1886780721", Key: "experiment_1", TrafficAllocation: trafficAllocation}
+	experiment2 := entities.Experiment{ID: "1886780722", Key: "experiment_1", TrafficAllocation: trafficAllocation}
+
+	hashBucketer := &recordingBucketer{Bucketer: NewXXHashBucketer(DefaultHashSeed)}
+	bucketer := NewExperimentBucketer(hashBucketer, WithExperimentSalts(map[string]string{"1886780722": "salt"}))
+
+	bucketer.Bucket("ppid1", experiment1, entities.Group{})
+	bucketer.Bucket("ppid1", experiment2, entities.Group{})
+	// the salt only applies to the experiment ID it is configured for, whatever the experiment key
+	assert.Equal(t, []string{"ppid118867807210", "ppid118867807220salt"}, hashBucketer.keys)
+}
+
+type recordingBucketer struct {
+	Bucketer
+	keys []string
+}
+
+func (r *recordingBucketer) BucketToEntity(bucketKey string, trafficAllocations []entities.Range) string {
+	r.keys = append(r.keys, bucketKey)
+	return r.Bucketer.BucketToEntity(bucketKey, trafficAllocations)
+}
+
+func TestBucketWithRevisionBucketing(t *testing.T) {
+	trafficAllocation := []entities.Range{{EntityID: "22222", EndOfRange: 10000}}
+	experiment := entities.Experiment{ID: "1886780721", Key: "experiment_1", Revision: 3, TrafficAllocation: trafficAllocation}
+	bucketByRevision := true
+	overridingExperiment := experiment
+	overridingExperiment.BucketByRevision = &bucketByRevision
+
+	hashBucketer := &recordingBucketer{Bucketer: NewXXHashBucketer(DefaultHashSeed)}
+	NewExperimentBucketer(hashBucketer).Bucket("ppid1", experiment, entities.Group{})
+	NewExperimentBucketer(hashBucketer, WithRevisionBucketing(false)).Bucket("ppid1", experiment, entities.Group{})
+	NewExperimentBucketer(hashBucketer, WithRevisionBucketing(false)).Bucket("ppid1", overridingExperiment, entities.Group{})
+	assert.Equal(t, []string{"ppid118867807213", "ppid11886780721", "ppid118867807213"}, hashBucketer.keys)
+}
+
+func TestBucketWithRampSchedule(t *testing.T) {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	experiment := entities.Experiment{
+		ID:                "1886780721",
+		Key:               "experiment_1",
+		Variations:        map[string]entities.Variation{"22222": {ID: "22222"}, "22223": {ID: "22223"}},
+		TrafficAllocation: []entities.Range{{EntityID: "22222", EndOfRange: 5000}, {EntityID: "22223", EndOfRange: 10000}},
+		RampSchedule: []entities.RampStep{
+			{StartTime: start, Percentage: 1},
+			{StartTime: start.Add(24 * time.Hour), Percentage: 5},
+			{StartTime: start.Add(72 * time.Hour), Percentage: 25},
+		},
+	}
+	now := start.Add(-time.Hour)
+	bucketer := NewExperimentBucketer(NewXXHashBucketer(DefaultHashSeed), WithRampClock(func() time.Time { return now }))
+
+	_, reason, _ := bucketer.Bucket("ppid1", experiment, entities.Group{})
+	assert.Equal(t, reasons.NotInRamp, reason)
+
+	// users let in stay in their variation as the ramp grows
+	bucketed := map[string]string{}
+	for _, step := range experiment.RampSchedule {
+		now = step.StartTime
+		count := 0
+		for i := 0; i < 10000; i++ {
+			bucketingID := "ppid" + strconv.Itoa(i)
+			variation, reason, _ := bucketer.Bucket(bucketingID, experiment, entities.Group{})
+			if variation == nil {
+				assert.Equal(t, reasons.NotInRamp, reason)
+				assert.NotContains(t, bucketed, bucketingID)
+				continue
+			}
+			if variationID, ok := bucketed[bucketingID]; ok {
+				assert.Equal(t, variationID, variation.ID)
+			}
+			bucketed[bucketingID] = variation.ID
+			count++
+		}
+		assert.InDelta(t, step.Percentage*100, count, step.Percentage*20+30)
+	}
+}
@@ -0,0 +1,85 @@
+/****************************************************************************
+ * Copyright 2026, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package bucketer //
+package bucketer
+
+import (
+	"github.com/WolffunService/experiment/pkg/decision/reasons"
+	"github.com/WolffunService/experiment/pkg/entities"
+)
+
+const (
+	// RandomGroupPolicy makes the experiments of a group mutually exclusive, splitting users by the group's traffic allocation
+	RandomGroupPolicy = "random"
+	// OverlappingGroupPolicy lets a user take part in every experiment of the group
+	OverlappingGroupPolicy = "overlapping"
+)
+
+// GroupPolicy decides whether a user of a group may be bucketed into one of the group's experiments
+type GroupPolicy interface {
+	// Admit returns whether the user may be bucketed into the experiment, along with the reason when they may not
+	Admit(bucketer Bucketer, bucketingID string, experiment entities.Experiment, group entities.Group) (bool, reasons.Reason)
+}
+
+// GroupPolicyFunc is an adapter allowing ordinary functions to be used as group policies
+type GroupPolicyFunc func(bucketer Bucketer, bucketingID string, experiment entities.Experiment, group entities.Group) (bool, reasons.Reason)
+
+// Admit calls f(bucketer, bucketingID, experiment, group)
+func (f GroupPolicyFunc) Admit(bucketer Bucketer, bucketingID string, experiment entities.Experiment, group entities.Group) (bool, reasons.Reason) {
+	return f(bucketer, bucketingID, experiment, group)
+}
+
+// WithGroupPolicy registers a group policy under the given name, replacing any built-in policy of the same name.
+// Users of a group whose policy is not registered are not bucketed into any of its experiments, unless a fallback is
+// set with WithUnknownGroupPolicy. Before group policies were pluggable, such users were bucketed as if the group used
+// the overlapping policy.
+func WithGroupPolicy(name string, policy GroupPolicy) ExperimentBucketerOptionFunc {
+	return func(b *HashExperimentBucketer) {
+		b.groupPolicies[name] = policy
+	}
+}
+
+// WithUnknownGroupPolicy sets the name of the registered policy applied to groups whose policy is not registered,
+// e.g. OverlappingGroupPolicy to bucket their users as if they were not grouped
+func WithUnknownGroupPolicy(name string) ExperimentBucketerOptionFunc {
+	return func(b *HashExperimentBucketer) {
+		b.unknownGroupPolicy = name
+	}
+}
+
+func defaultGroupPolicies() map[string]GroupPolicy {
+	return map[string]GroupPolicy{
+		RandomGroupPolicy:      GroupPolicyFunc(admitRandom),
+		OverlappingGroupPolicy: GroupPolicyFunc(admitOverlapping),
+	}
+}
+
+// admitRandom buckets the user into a single experiment of the group
+func admitRandom(bucketer Bucketer, bucketingID string, experiment entities.Experiment, group entities.Group) (bool, reasons.Reason) {
+	bucketKey := bucketingID + group.ID
+	bucketedExperimentID := bucketer.BucketToEntity(bucketKey, group.TrafficAllocation)
+	if bucketedExperimentID == "" || bucketedExperimentID != experiment.ID {
+		// User is not bucketed into provided experiment in mutex group
+		return false, reasons.NotBucketedIntoVariation
+	}
+	return true, ""
+}
+
+// admitOverlapping leaves each experiment of the group to bucket the user independently
+func admitOverlapping(Bucketer, string, entities.Experiment, entities.Group) (bool, reasons.Reason) {
+	return true, ""
+}
@@ -0,0 +1,115 @@
+package bucketer
+
+import (
+	"testing"
+
+	"github.com/WolffunService/experiment/pkg/decision/reasons"
+	"github.com/WolffunService/experiment/pkg/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+var groupPolicyExperiments = []entities.Experiment{
+	{
+		ID:                "1886780721",
+		Key:               "experiment_1",
+		Variations:        map[string]entities.Variation{"22222": {ID: "22222", Key: "exp_1_var_1"}},
+		TrafficAllocation: []entities.Range{{EntityID: "22222", EndOfRange: 10000}},
+		GroupID:           "1886780722",
+	},
+	{
+		ID:                "1886780723",
+		Key:               "experiment_2",
+		Variations:        map[string]entities.Variation{"22224": {ID: "22224", Key: "exp_2_var_1"}},
+		TrafficAllocation: []entities.Range{{EntityID: "22224", EndOfRange: 10000}},
+		GroupID:           "1886780722",
+	},
+}
+
+func TestBucketOverlappingGroup(t *testing.T) {
+	group := entities.Group{ID: "1886780722", Policy: OverlappingGroupPolicy}
+	bucketer := NewExperimentBucketer(NewXXHashBucketer(DefaultHashSeed))
+
+	// the user takes part in every experiment of an overlapping group
+	for _, experiment := range groupPolicyExperiments {
+		bucketedVariation, reason, _ := bucketer.Bucket("ppid1", experiment, group)
+		if assert.NotNil(t, bucketedVariation) {
+			assert.Equal(t, experiment.TrafficAllocation[0].EntityID, bucketedVariation.ID)
+		}
+		assert.Equal(t, reasons.BucketedIntoVariation, reason)
+	}
+}
+
+func TestBucketRandomGroupIsExclusive(t *testing.T) {
+	group := entities.Group{
+		ID:     "1886780722",
+		Policy: RandomGroupPolicy,
+		TrafficAllocation: []entities.Range{
+			{EntityID: "1886780721", EndOfRange: 5000},
+			{EntityID: "1886780723", EndOfRange: 10000},
+		},
+	}
+	bucketer := NewExperimentBucketer(NewXXHashBucketer(DefaultHashSeed))
+
+	for _, bucketingID := range []string{"ppid1", "ppid2", "ppid3", "ppid4"} {
+		bucketedCount := 0
+		for _, experiment := range groupPolicyExperiments {
+			if bucketedVariation, _, _ := bucketer.Bucket(bucketingID, experiment, group); bucketedVariation != nil {
+				bucketedCount++
+			}
+		}
+		assert.Equal(t, 1, bucketedCount)
+	}
+}
+
+func TestBucketCustomGroupPolicy(t *testing.T) {
+	// a priority-ordered policy only lets users into the first experiment of the group
+	priority := GroupPolicyFunc(func(_ Bucketer, _ string, experiment entities.Experiment, group entities.Group) (bool, reasons.Reason) {
+		if experiment.ID != group.TrafficAllocation[0].EntityID {
+			return false, reasons.NotInGroup
+		}
+		return true, ""
+	})
+	group := entities.Group{
+		ID:                "1886780722",
+		Policy:            "priority",
+		TrafficAllocation: []entities.Range{{EntityID: "1886780723", EndOfRange: 10000}},
+	}
+	bucketer := NewExperimentBucketer(NewXXHashBucketer(DefaultHashSeed), WithGroupPolicy("priority", priority))
+
+	bucketedVariation, reason, _ := bucketer.Bucket("ppid1", groupPolicyExperiments[0], group)
+	assert.Nil(t, bucketedVariation)
+	assert.Equal(t, reasons.NotInGroup, reason)
+
+	bucketedVariation, reason, _ = bucketer.Bucket("ppid1", groupPolicyExperiments[1], group)
+	assert.NotNil(t, bucketedVariation)
+	assert.Equal(t, reasons.BucketedIntoVariation, reason)
+}
+
+func TestBucketUnknownGroupPolicy(t *testing.T) {
+	group := entities.Group{ID: "1886780722", Policy: "capacity"}
+	bucketer := NewExperimentBucketer(NewXXHashBucketer(DefaultHashSeed))
+
+	bucketedVariation, reason, err := bucketer.Bucket("ppid1", groupPolicyExperiments[0], group)
+	assert.NoError(t, err)
+	assert.Nil(t, bucketedVariation)
+	assert.Equal(t, reasons.UnknownGroupPolicy, reason)
+}
+
+func TestBucketUnknownGroupPolicyFallback(t *testing.T) {
+	group := entities.Group{ID: "1886780722", Policy: "capacity"}
+	bucketer := NewExperimentBucketer(NewXXHashBucketer(DefaultHashSeed), WithUnknownGroupPolicy(OverlappingGroupPolicy))
+
+	for _, experiment := range groupPolicyExperiments {
+		bucketedVariation, reason, err := bucketer.Bucket("ppid1", experiment, group)
+		assert.NoError(t, err)
+		assert.NotNil(t, bucketedVariation)
+		assert.Equal(t, reasons.BucketedIntoVariation, reason)
+	}
+
+	// a fallback that is not registered either still excludes the user
+	bucketer = NewExperimentBucketer(NewXXHashBucketer(DefaultHashSeed), WithUnknownGroupPolicy("missing"))
+	bucketedVariation, reason, err := bucketer.Bucket("ppid1", groupPolicyExperiments[0], group)
+	assert.NoError(t, err)
+	assert.Nil(t, bucketedVariation)
+	assert.Equal(t, reasons.UnknownGroupPolicy, reason)
+}
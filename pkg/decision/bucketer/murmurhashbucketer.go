@@ -66,8 +66,11 @@ func (b MurmurhashBucketer) Generate(bucketingKey string) int {
 
 // BucketToEntity buckets into a traffic against given bucketKey
 func (b MurmurhashBucketer) BucketToEntity(bucketKey string, trafficAllocations []entities.Range) (entityID string) {
-	bucketValue := b.Generate(bucketKey)
+	return bucketToEntity(b.Generate(bucketKey), trafficAllocations)
+}
 
+// bucketToEntity returns the entity of the first range whose end lies beyond bucketValue
+func bucketToEntity(bucketValue int, trafficAllocations []entities.Range) (entityID string) {
 	var currentEndOfRange int
 	for _, trafficAllocationRange := range trafficAllocations {
 		currentEndOfRange = trafficAllocationRange.EndOfRange
@@ -78,3 +81,8 @@ func (b MurmurhashBucketer) BucketToEntity(bucketKey string, trafficAllocations
 
 	return ""
 }
+
+// scaleHash maps a 64 bit hash code onto the [0, maxTrafficValue) bucket range
+func scaleHash(hashCode uint64) int {
+	return int((hashCode >> 32) * maxTrafficValue >> 32)
+}
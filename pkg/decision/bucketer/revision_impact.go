@@ -0,0 +1,71 @@
+/****************************************************************************
+ * Copyright 2026, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package bucketer //
+package bucketer
+
+import (
+	"github.com/WolffunService/experiment/pkg/entities"
+)
+
+// ExperimentRevision is a revision of an experiment together with the group it belongs to, if any
+type ExperimentRevision struct {
+	Experiment entities.Experiment
+	Group      entities.Group
+}
+
+// RevisionImpact reports how bucketing of a sample of users differs between two experiment revisions
+type RevisionImpact struct {
+	SampledUsers int
+	ChangedUsers int
+	// Transitions counts the changed users by their variation key before and after, an empty key meaning the user
+	// was not bucketed into a variation
+	Transitions map[[2]string]int
+}
+
+// ChangedRatio returns the share of sampled users whose variation changes
+func (i RevisionImpact) ChangedRatio() float64 {
+	if i.SampledUsers == 0 {
+		return 0
+	}
+	return float64(i.ChangedUsers) / float64(i.SampledUsers)
+}
+
+// CompareRevisions buckets every bucketing ID into both revisions and reports how many users would change variation,
+// which helps judging the impact of publishing the new revision
+func CompareRevisions(experimentBucketer ExperimentBucketer, before, after ExperimentRevision, bucketingIDs []string) RevisionImpact {
+	impact := RevisionImpact{
+		SampledUsers: len(bucketingIDs),
+		Transitions:  map[[2]string]int{},
+	}
+	for _, bucketingID := range bucketingIDs {
+		beforeKey := bucketedVariationKey(experimentBucketer, bucketingID, before)
+		afterKey := bucketedVariationKey(experimentBucketer, bucketingID, after)
+		if beforeKey != afterKey {
+			impact.ChangedUsers++
+			impact.Transitions[[2]string{beforeKey, afterKey}]++
+		}
+	}
+	return impact
+}
+
+func bucketedVariationKey(experimentBucketer ExperimentBucketer, bucketingID string, revision ExperimentRevision) string {
+	variation, _, err := experimentBucketer.Bucket(bucketingID, revision.Experiment, revision.Group)
+	if err != nil || variation == nil {
+		return ""
+	}
+	return variation.Key
+}
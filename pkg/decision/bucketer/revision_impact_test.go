@@ -0,0 +1,49 @@
+package bucketer
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/WolffunService/experiment/pkg/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareRevisions(t *testing.T) {
+	variations := map[string]entities.Variation{
+		"22222": {ID: "22222", Key: "a"},
+		"22223": {ID: "22223", Key: "b"},
+	}
+	before := entities.Experiment{
+		ID:                "1886780721",
+		Key:               "experiment_1",
+		Revision:          1,
+		Variations:        variations,
+		TrafficAllocation: []entities.Range{{EntityID: "22222", EndOfRange: 5000}, {EntityID: "22223", EndOfRange: 10000}},
+	}
+	bumped := before
+	bumped.Revision = 2
+	reallocated := before
+	reallocated.TrafficAllocation = []entities.Range{{EntityID: "22222", EndOfRange: 6000}, {EntityID: "22223", EndOfRange: 10000}}
+
+	bucketingIDs := make([]string, 10000)
+	for i := range bucketingIDs {
+		bucketingIDs[i] = fmt.Sprintf("user_%d", i)
+	}
+	experimentBucketer := NewExperimentBucketer(NewXXHashBucketer(DefaultHashSeed))
+
+	// bumping the revision re-randomizes, so about half of the users change variation
+	impact := CompareRevisions(experimentBucketer, ExperimentRevision{Experiment: before}, ExperimentRevision{Experiment: bumped}, bucketingIDs)
+	assert.Equal(t, 10000, impact.SampledUsers)
+	assert.InDelta(t, 0.5, impact.ChangedRatio(), 0.03)
+	assert.Equal(t, impact.ChangedUsers, impact.Transitions[[2]string{"a", "b"}]+impact.Transitions[[2]string{"b", "a"}])
+
+	// without revision bucketing only the users in the reallocated 10% change
+	experimentBucketer = NewExperimentBucketer(NewXXHashBucketer(DefaultHashSeed), WithRevisionBucketing(false))
+	impact = CompareRevisions(experimentBucketer, ExperimentRevision{Experiment: before}, ExperimentRevision{Experiment: bumped}, bucketingIDs)
+	assert.Equal(t, 0, impact.ChangedUsers)
+	impact = CompareRevisions(experimentBucketer, ExperimentRevision{Experiment: before}, ExperimentRevision{Experiment: reallocated}, bucketingIDs)
+	assert.InDelta(t, 0.1, impact.ChangedRatio(), 0.02)
+	assert.Equal(t, map[[2]string]int{{"b", "a"}: impact.ChangedUsers}, impact.Transitions)
+
+	assert.Equal(t, 0.0, CompareRevisions(experimentBucketer, ExperimentRevision{}, ExperimentRevision{}, nil).ChangedRatio())
+}
@@ -0,0 +1,48 @@
+/****************************************************************************
+ * Copyright 2026, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package bucketer //
+package bucketer
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+
+	"github.com/WolffunService/experiment/pkg/entities"
+)
+
+// SHA256Bucketer generates the bucketing value from the SHA-256 digest of the seeded bucketing key
+type SHA256Bucketer struct {
+	hashSeed uint32
+}
+
+// NewSHA256Bucketer returns a new instance of the SHA-256 bucketer
+func NewSHA256Bucketer(hashSeed uint32) *SHA256Bucketer {
+	return &SHA256Bucketer{hashSeed: hashSeed}
+}
+
+// Generate returns a bucketing value for bucketing key
+func (b SHA256Bucketer) Generate(bucketingKey string) int {
+	data := make([]byte, 4, 4+len(bucketingKey))
+	binary.BigEndian.PutUint32(data, b.hashSeed)
+	digest := sha256.Sum256(append(data, bucketingKey...))
+	return scaleHash(binary.BigEndian.Uint64(digest[:8]))
+}
+
+// BucketToEntity buckets into a traffic against given bucketKey
+func (b SHA256Bucketer) BucketToEntity(bucketKey string, trafficAllocations []entities.Range) (entityID string) {
+	return bucketToEntity(b.Generate(bucketKey), trafficAllocations)
+}
@@ -0,0 +1,31 @@
+package bucketer
+
+import (
+	"testing"
+
+	"github.com/WolffunService/experiment/pkg/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSHA256BucketerGenerate(t *testing.T) {
+	bucketer := NewSHA256Bucketer(1)
+
+	assert.Equal(t, 9219, bucketer.Generate("ppid11886780721"))
+	assert.Equal(t, 1381, bucketer.Generate("ppid21886780721"))
+	assert.Equal(t, 4894, bucketer.Generate("ppid21886780722"))
+	assert.Equal(t, 5255, bucketer.Generate("ppid31886780721"))
+	assert.NotEqual(t, bucketer.Generate("ppid11886780721"), NewSHA256Bucketer(2).Generate("ppid11886780721"))
+}
+
+func TestSHA256BucketerBucketToEntity(t *testing.T) {
+	bucketer := NewSHA256Bucketer(1)
+	trafficAlloc := []entities.Range{
+		{EntityID: "variation_1", EndOfRange: 5000},
+		{EntityID: "variation_2", EndOfRange: 9000},
+	}
+
+	assert.Equal(t, "variation_1", bucketer.BucketToEntity("ppid21886780722", trafficAlloc))
+	assert.Equal(t, "variation_2", bucketer.BucketToEntity("ppid31886780721", trafficAlloc))
+	// bucket outside of range (not in experiment)
+	assert.Equal(t, "", bucketer.BucketToEntity("ppid11886780721", trafficAlloc))
+}
@@ -0,0 +1,38 @@
+package bucketer
+
+import (
+	"testing"
+
+	"github.com/WolffunService/experiment/pkg/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestXXHash64(t *testing.T) {
+	// reference vectors of the XXH64 algorithm
+	assert.Equal(t, uint64(0xef46db3751d8e999), xxhash64([]byte(""), 0))
+	assert.Equal(t, uint64(0x44bc2cf5ad770999), xxhash64([]byte("abc"), 0))
+	assert.Equal(t, uint64(0xfbcea83c8a378bf1), xxhash64([]byte("Nobody inspects the spammish repetition"), 0))
+}
+
+func TestXXHashBucketerGenerate(t *testing.T) {
+	bucketer := NewXXHashBucketer(1)
+
+	assert.Equal(t, 2944, bucketer.Generate("ppid11886780721"))
+	assert.Equal(t, 5506, bucketer.Generate("ppid21886780721"))
+	assert.Equal(t, 8875, bucketer.Generate("ppid21886780722"))
+	assert.Equal(t, 9091, bucketer.Generate("ppid31886780721"))
+	assert.NotEqual(t, bucketer.Generate("ppid11886780721"), NewXXHashBucketer(2).Generate("ppid11886780721"))
+}
+
+func TestXXHashBucketerBucketToEntity(t *testing.T) {
+	bucketer := NewXXHashBucketer(1)
+	trafficAlloc := []entities.Range{
+		{EntityID: "variation_1", EndOfRange: 5000},
+		{EntityID: "variation_2", EndOfRange: 9000},
+	}
+
+	assert.Equal(t, "variation_1", bucketer.BucketToEntity("ppid11886780721", trafficAlloc))
+	assert.Equal(t, "variation_2", bucketer.BucketToEntity("ppid21886780722", trafficAlloc))
+	// bucket outside of range (not in experiment)
+	assert.Equal(t, "", bucketer.BucketToEntity("ppid31886780721", trafficAlloc))
+}
@@ -21,6 +21,8 @@ import (
 	"fmt"
 
 	"github.com/WolffunService/experiment/pkg/decide"
+	"github.com/WolffunService/experiment/pkg/decision/bucketer"
+	pkgReasons "github.com/WolffunService/experiment/pkg/decision/reasons"
 	"github.com/WolffunService/experiment/pkg/entities"
 	"github.com/WolffunService/experiment/pkg/logging"
 )
@@ -42,11 +44,27 @@ func WithOverrideStore(overrideStore ExperimentOverrideStore) CESOptionFunc {
 	}
 }
 
+// WithBucketer sets the experiment bucketer used to bucket users, instead of the default murmurhash one
+func WithBucketer(experimentBucketer bucketer.ExperimentBucketer) CESOptionFunc {
+	return func(f *CompositeExperimentService) {
+		f.bucketer = experimentBucketer
+	}
+}
+
+// WithBanditStore enables bandit allocation for experiments carrying bandit metadata, keeping their state in the store
+func WithBanditStore(banditStore BanditStore) CESOptionFunc {
+	return func(f *CompositeExperimentService) {
+		f.banditStore = banditStore
+	}
+}
+
 // CompositeExperimentService bridges together the various experiment decision services that ship by default with the SDK
 type CompositeExperimentService struct {
 	experimentServices []ExperimentService
 	overrideStore      ExperimentOverrideStore
 	userProfileService UserProfileService
+	bucketer           bucketer.ExperimentBucketer
+	banditStore        BanditStore
 	logger             logging.OptimizelyLogProducer
 }
 
@@ -55,7 +73,7 @@ func NewCompositeExperimentService(sdkKey string, options ...CESOptionFunc) *Com
 	// These decision services are applied in order:
 	// 1. Overrides (if supplied)
 	// 2. Whitelist
-	// 3. Bucketing (with User profile integration if supplied)
+	// 3. Bucketing (with bandit allocation and User profile integration if supplied)
 	compositeExperimentService := &CompositeExperimentService{logger: logging.GetLogger(sdkKey, "CompositeExperimentService")}
 	for _, opt := range options {
 		opt(compositeExperimentService)
@@ -71,11 +89,18 @@ func NewCompositeExperimentService(sdkKey string, options ...CESOptionFunc) *Com
 	}
 
 	experimentBucketerService := NewExperimentBucketerService(logging.GetLogger(sdkKey, "ExperimentBucketerService"))
+	if compositeExperimentService.bucketer != nil {
+		experimentBucketerService.bucketer = compositeExperimentService.bucketer
+	}
+	var bucketingService ExperimentService = experimentBucketerService
+	if compositeExperimentService.banditStore != nil {
+		bucketingService = NewBanditExperimentService(compositeExperimentService.banditStore, experimentBucketerService, logging.GetLogger(sdkKey, "BanditExperimentService"))
+	}
 	if compositeExperimentService.userProfileService != nil {
-		persistingExperimentService := NewPersistingExperimentService(compositeExperimentService.userProfileService, experimentBucketerService, logging.GetLogger(sdkKey, "PersistingExperimentService"))
+		persistingExperimentService := NewPersistingExperimentService(compositeExperimentService.userProfileService, bucketingService, logging.GetLogger(sdkKey, "PersistingExperimentService"))
 		experimentServices = append(experimentServices, persistingExperimentService)
 	} else {
-		experimentServices = append(experimentServices, experimentBucketerService)
+		experimentServices = append(experimentServices, bucketingService)
 	}
 	compositeExperimentService.experimentServices = experimentServices
 
@@ -86,6 +111,12 @@ func NewCompositeExperimentService(sdkKey string, options ...CESOptionFunc) *Com
 func (s CompositeExperimentService) GetDecision(decisionContext ExperimentDecisionContext, userContext entities.UserContext, options *decide.Options) (decision ExperimentDecision, reasons decide.DecisionReasons, err error) {
 	// Run through the various decision services until we get a decision
 	reasons = decide.NewDecisionReasons(options)
+	if experiment := decisionContext.Experiment; experiment != nil && !experiment.IsRunning() {
+		logMessage := reasons.AddInfo(logging.ExperimentNotRunning.String(), experiment.Key, experiment.Status)
+		s.logger.Info(logMessage)
+		decision.Reason = pkgReasons.ExperimentNotRunning
+		return decision, reasons, nil
+	}
 	for _, experimentService := range s.experimentServices {
 		var decisionReasons decide.DecisionReasons
 		decision, decisionReasons, err = experimentService.GetDecision(decisionContext, userContext, options)
@@ -23,6 +23,7 @@ import (
 	"github.com/stretchr/testify/suite"
 
 	"github.com/WolffunService/experiment/pkg/decide"
+	"github.com/WolffunService/experiment/pkg/decision/reasons"
 	"github.com/WolffunService/experiment/pkg/entities"
 	"github.com/WolffunService/experiment/pkg/logging"
 )
@@ -102,6 +103,33 @@ func (s *CompositeExperimentTestSuite) TestGetDecisionFallthrough() {
 	s.mockExperimentService2.AssertExpectations(s.T())
 }
 
+func (s *CompositeExperimentTestSuite) TestGetDecisionExperimentNotRunning() {
+	// test that no decision service is called for an experiment that is not running
+	testUserContext := entities.UserContext{
+		ID: "test_user_1",
+	}
+	pausedExperiment := testExp1111
+	pausedExperiment.Status = entities.ExperimentStatusPaused
+	decisionContext := ExperimentDecisionContext{
+		Experiment:    &pausedExperiment,
+		ProjectConfig: s.mockConfig,
+	}
+
+	compositeExperimentService := &CompositeExperimentService{
+		experimentServices: []ExperimentService{s.mockExperimentService, s.mockExperimentService2},
+		logger:             logging.GetLogger("sdkKey", "CompositeExperimentService"),
+	}
+	s.options.IncludeReasons = true
+	decision, rsons, err := compositeExperimentService.GetDecision(decisionContext, testUserContext, s.options)
+
+	s.NoError(err)
+	s.Nil(decision.Variation)
+	s.Equal(reasons.ExperimentNotRunning, decision.Reason)
+	s.Equal([]string{`Experiment "test_experiment_1111" is not running (status "Paused").`}, rsons.ToReport())
+	s.mockExperimentService.AssertNotCalled(s.T(), "GetDecision")
+	s.mockExperimentService2.AssertNotCalled(s.T(), "GetDecision")
+}
+
 func (s *CompositeExperimentTestSuite) TestGetDecisionNoDecisionsMade() {
 	// test when no decisions are made
 	testUserContext := entities.UserContext{
@@ -179,6 +207,25 @@ func (s *CompositeExperimentTestSuite) TestNewCompositeExperimentServiceWithCust
 	s.Equal(mockExperimentOverrideStore, compositeExperimentService.overrideStore)
 }
 
+func (s *CompositeExperimentTestSuite) TestNewCompositeExperimentServiceWithBucketer() {
+	mockBucketer := new(MockBucketer)
+	compositeExperimentService := NewCompositeExperimentService("", WithBucketer(mockBucketer))
+	s.Equal(mockBucketer, compositeExperimentService.bucketer)
+	experimentBucketerService := compositeExperimentService.experimentServices[1].(*ExperimentBucketerService)
+	s.Equal(mockBucketer, experimentBucketerService.bucketer)
+}
+
+func (s *CompositeExperimentTestSuite) TestNewCompositeExperimentServiceWithBanditStore() {
+	banditStore := NewMapBanditStore()
+	compositeExperimentService := NewCompositeExperimentService("", WithBanditStore(banditStore))
+	s.IsType(&BanditExperimentService{}, compositeExperimentService.experimentServices[1])
+
+	// bandit decisions stay sticky through the user profile service
+	compositeExperimentService = NewCompositeExperimentService("", WithBanditStore(banditStore), WithUserProfileService(new(MockUserProfileService)))
+	persistingExperimentService := compositeExperimentService.experimentServices[1].(*PersistingExperimentService)
+	s.IsType(&BanditExperimentService{}, persistingExperimentService.experimentBucketedService)
+}
+
 func TestCompositeExperimentTestSuite(t *testing.T) {
 	suite.Run(t, new(CompositeExperimentTestSuite))
 }
@@ -21,6 +21,7 @@ import (
 	"fmt"
 
 	"github.com/WolffunService/experiment/pkg/decide"
+	"github.com/WolffunService/experiment/pkg/decision/bucketer"
 	"github.com/WolffunService/experiment/pkg/entities"
 	"github.com/WolffunService/experiment/pkg/logging"
 )
@@ -31,15 +32,38 @@ type CompositeFeatureService struct {
 	logger          logging.OptimizelyLogProducer
 }
 
+// CFSOptionFunc is used to assign optional configuration options to the CompositeFeatureService
+type CFSOptionFunc func(*CompositeFeatureService)
+
+// WithFeatureBucketer sets the experiment bucketer used to bucket users into holdouts and rollout rules, feature tests
+// are bucketed by the experiment service passed to NewCompositeFeatureService
+func WithFeatureBucketer(experimentBucketer bucketer.ExperimentBucketer) CFSOptionFunc {
+	return func(f *CompositeFeatureService) {
+		for _, featureService := range f.featureServices {
+			switch service := featureService.(type) {
+			case *HoldoutService:
+				WithHoldoutBucketer(experimentBucketer)(service)
+			case *RolloutService:
+				WithRolloutBucketer(experimentBucketer)(service)
+			}
+		}
+	}
+}
+
 // NewCompositeFeatureService returns a new instance of the CompositeFeatureService
-func NewCompositeFeatureService(sdkKey string, compositeExperimentService ExperimentService) *CompositeFeatureService {
-	return &CompositeFeatureService{
+func NewCompositeFeatureService(sdkKey string, compositeExperimentService ExperimentService, options ...CFSOptionFunc) *CompositeFeatureService {
+	compositeFeatureService := &CompositeFeatureService{
 		logger: logging.GetLogger(sdkKey, "CompositeFeatureService"),
 		featureServices: []FeatureService{
+			NewHoldoutService(sdkKey),
 			NewFeatureExperimentService(logging.GetLogger(sdkKey, "FeatureExperimentService"), compositeExperimentService),
 			NewRolloutService(sdkKey),
 		},
 	}
+	for _, opt := range options {
+		opt(compositeFeatureService)
+	}
+	return compositeFeatureService
 }
 
 // GetDecision returns a decision for the given feature and user context
@@ -169,9 +169,19 @@ func (s *CompositeFeatureServiceTestSuite) TestNewCompositeFeatureService() {
 	// Assert that the service is instantiated with the correct child services in the right order
 	compositeExperimentService := NewCompositeExperimentService("")
 	compositeFeatureService := NewCompositeFeatureService("", compositeExperimentService)
-	s.Equal(2, len(compositeFeatureService.featureServices))
-	s.IsType(&FeatureExperimentService{compositeExperimentService: compositeExperimentService}, compositeFeatureService.featureServices[0])
-	s.IsType(&RolloutService{}, compositeFeatureService.featureServices[1])
+	s.Equal(3, len(compositeFeatureService.featureServices))
+	s.IsType(&HoldoutService{}, compositeFeatureService.featureServices[0])
+	s.IsType(&FeatureExperimentService{compositeExperimentService: compositeExperimentService}, compositeFeatureService.featureServices[1])
+	s.IsType(&RolloutService{}, compositeFeatureService.featureServices[2])
+}
+
+func (s *CompositeFeatureServiceTestSuite) TestNewCompositeFeatureServiceWithFeatureBucketer() {
+	mockBucketer := new(MockBucketer)
+	compositeFeatureService := NewCompositeFeatureService("", NewCompositeExperimentService(""), WithFeatureBucketer(mockBucketer))
+	holdoutService := compositeFeatureService.featureServices[0].(*HoldoutService)
+	s.Equal(mockBucketer, holdoutService.experimentBucketerService.(*ExperimentBucketerService).bucketer)
+	rolloutService := compositeFeatureService.featureServices[2].(*RolloutService)
+	s.Equal(mockBucketer, rolloutService.experimentBucketerService.(*ExperimentBucketerService).bucketer)
 }
 
 func TestCompositeFeatureTestSuite(t *testing.T) {
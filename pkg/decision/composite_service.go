@@ -45,6 +45,21 @@ func WithCompositeExperimentService(compositeExperimentService ExperimentService
 	}
 }
 
+// WithCompositeFeatureService sets the composite feature service on the CompositeService
+func WithCompositeFeatureService(compositeFeatureService FeatureService) CSOptionFunc {
+	return func(f *CompositeService) {
+		f.compositeFeatureService = compositeFeatureService
+	}
+}
+
+// WithNotificationCenter sets the notification center Decision notifications are sent to, instead of the one the
+// registry holds for the SDK key
+func WithNotificationCenter(notificationCenter notification.Center) CSOptionFunc {
+	return func(f *CompositeService) {
+		f.notificationCenter = notificationCenter
+	}
+}
+
 // NewCompositeService returns a new instance of the CompositeService with the defaults
 func NewCompositeService(sdkKey string, options ...CSOptionFunc) *CompositeService {
 	compositeService := &CompositeService{
@@ -59,7 +74,9 @@ func NewCompositeService(sdkKey string, options ...CSOptionFunc) *CompositeServi
 	if compositeService.compositeExperimentService == nil {
 		compositeService.compositeExperimentService = NewCompositeExperimentService(sdkKey)
 	}
-	compositeService.compositeFeatureService = NewCompositeFeatureService(sdkKey, compositeService.compositeExperimentService)
+	if compositeService.compositeFeatureService == nil {
+		compositeService.compositeFeatureService = NewCompositeFeatureService(sdkKey, compositeService.compositeExperimentService)
+	}
 
 	return compositeService
 }
@@ -80,6 +80,16 @@ func (s *CompositeServiceFeatureTestSuite) TestNewCompositeServiceWithCustomOpti
 	compositeService := NewCompositeService("sdk_key", WithCompositeExperimentService(compositeExperimentService))
 	s.IsType(compositeExperimentService, compositeService.compositeExperimentService)
 	s.IsType(&CompositeFeatureService{}, compositeService.compositeFeatureService)
+
+	compositeFeatureService := NewCompositeFeatureService("", compositeExperimentService)
+	compositeService = NewCompositeService("sdk_key", WithCompositeFeatureService(compositeFeatureService))
+	s.True(compositeFeatureService == compositeService.compositeFeatureService)
+}
+
+func (s *CompositeServiceFeatureTestSuite) TestNewCompositeServiceWithNotificationCenter() {
+	notificationCenter := notification.NewNotificationCenter()
+	compositeService := NewCompositeService("sdk_key", WithNotificationCenter(notificationCenter))
+	s.True(notificationCenter == compositeService.notificationCenter)
 }
 
 type CompositeServiceExperimentTestSuite struct {
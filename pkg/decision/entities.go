@@ -53,6 +53,8 @@ const (
 	Rollout Source = "rollout"
 	// FeatureTest - the decision came from a feature test
 	FeatureTest Source = "feature-test"
+	// Holdout - the decision came from a global holdout
+	Holdout Source = "holdout"
 )
 
 // Decision contains base information about a decision
@@ -74,6 +74,10 @@ func (s ExperimentBucketerService) GetDecision(decisionContext ExperimentDecisio
 	if experiment.GroupID != "" {
 		// @TODO: figure out what to do if group is not found
 		group, _ = decisionContext.ProjectConfig.GetGroupByID(experiment.GroupID)
+	} else if experiment.LayerID != "" {
+		if layer, err := decisionContext.ProjectConfig.GetLayerByID(experiment.LayerID); err == nil {
+			group = layer.ExclusionGroup()
+		}
 	}
 	// bucket user into a variation
 	bucketingID, err := userContext.GetBucketingID()
@@ -87,6 +91,14 @@ func (s ExperimentBucketerService) GetDecision(decisionContext ExperimentDecisio
 	}
 	// @TODO: handle error from bucketer
 	variation, reason, _ := s.bucketer.Bucket(bucketingID, *experiment, group)
+	switch reason {
+	case pkgReasons.UnknownGroupPolicy:
+		logMessage := reasons.AddInfo(logging.UnknownGroupPolicy.String(), group.ID, experiment.Key, group.Policy)
+		s.logger.Warning(logMessage)
+	case pkgReasons.NotInRamp:
+		logMessage := reasons.AddInfo(logging.UserNotInRamp.String(), userContext.ID, experiment.Key)
+		s.logger.Debug(logMessage)
+	}
 	experimentDecision.Reason = reason
 	experimentDecision.Variation = variation
 	return experimentDecision, reasons, nil
@@ -20,11 +20,15 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/WolffunService/experiment/pkg/config/datafilebuilder"
+	"github.com/WolffunService/experiment/pkg/config/datafileprojectconfig"
 	"github.com/WolffunService/experiment/pkg/decide"
+	"github.com/WolffunService/experiment/pkg/decision/bucketer"
 	"github.com/WolffunService/experiment/pkg/decision/reasons"
 	"github.com/WolffunService/experiment/pkg/logging"
 
 	"github.com/WolffunService/experiment/pkg/entities"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
@@ -184,6 +188,74 @@ func (s *ExperimentBucketerTestSuite) TestGetDecisionWithTargetingFails() {
 
 }
 
+func (s *ExperimentBucketerTestSuite) TestGetDecisionUnknownGroupPolicy() {
+	testUserContext := entities.UserContext{
+		ID: "test_user_1",
+	}
+	testExp := testExp1111
+	testExp.GroupID = "group_1"
+	testGroup := entities.Group{ID: "group_1", Policy: "capacity"}
+
+	testDecisionContext := ExperimentDecisionContext{
+		Experiment:    &testExp,
+		ProjectConfig: s.mockConfig,
+	}
+	s.mockConfig.On("GetGroupByID", "group_1").Return(testGroup, nil)
+	s.mockLogger.On("Debug", fmt.Sprintf(logging.ExperimentAudiencesEvaluatedTo.String(), "test_experiment_1111", true))
+	s.mockLogger.On("Warning", `Group "group_1" of experiment "test_experiment_1111" uses unknown policy "capacity", so the user is not bucketed.`)
+	experimentBucketerService := ExperimentBucketerService{
+		bucketer: bucketer.NewMurmurhashExperimentBucketer(s.mockLogger, bucketer.DefaultHashSeed),
+		logger:   s.mockLogger,
+	}
+	s.options.IncludeReasons = true
+	decision, rsons, err := experimentBucketerService.GetDecision(testDecisionContext, testUserContext, s.options)
+	messages := rsons.ToReport()
+	s.Len(messages, 2)
+	s.Equal(`Group "group_1" of experiment "test_experiment_1111" uses unknown policy "capacity", so the user is not bucketed.`, messages[1])
+	s.Nil(decision.Variation)
+	s.Equal(reasons.UnknownGroupPolicy, decision.Reason)
+	s.NoError(err)
+	s.mockLogger.AssertExpectations(s.T())
+}
+
 func TestExperimentBucketerTestSuite(t *testing.T) {
 	suite.Run(t, new(ExperimentBucketerTestSuite))
 }
+
+func TestExperimentBucketerServiceWithLayers(t *testing.T) {
+	builder := datafilebuilder.New()
+	flag := builder.Flag("economy")
+	for _, layerKey := range []string{"pricing", "rewards"} {
+		for _, experimentKey := range []string{layerKey + "_a", layerKey + "_b"} {
+			flag.Experiment(experimentKey).Variation("on", true).Layer(layerKey, 50)
+		}
+	}
+	projectConfig, err := datafileprojectconfig.NewDatafileProjectConfig(builder.MustBuild(), logging.GetLogger("", "DatafileProjectConfig"))
+	assert.NoError(t, err)
+	experimentBucketerService := NewExperimentBucketerService(logging.GetLogger("", "ExperimentBucketerService"))
+
+	inExperiment := func(experimentKey, userID string) bool {
+		experiment, err := projectConfig.GetExperimentByKey(experimentKey)
+		assert.NoError(t, err)
+		decisionContext := ExperimentDecisionContext{Experiment: &experiment, ProjectConfig: projectConfig}
+		decision, _, err := experimentBucketerService.GetDecision(decisionContext, entities.UserContext{ID: userID}, &decide.Options{})
+		assert.NoError(t, err)
+		return decision.Variation != nil
+	}
+
+	combinations := map[string]int{}
+	for i := 0; i < 2000; i++ {
+		userID := fmt.Sprintf("user_%d", i)
+		// every user is in exactly one experiment of each layer
+		pricingA, pricingB := inExperiment("pricing_a", userID), inExperiment("pricing_b", userID)
+		rewardsA, rewardsB := inExperiment("rewards_a", userID), inExperiment("rewards_b", userID)
+		assert.True(t, pricingA != pricingB)
+		assert.True(t, rewardsA != rewardsB)
+		combinations[fmt.Sprintf("%v/%v", pricingA, rewardsA)]++
+	}
+	// and the layers are orthogonal, so every combination of their experiments is about as likely
+	assert.Len(t, combinations, 4)
+	for combination, count := range combinations {
+		assert.InDelta(t, 500, count, 80, combination)
+	}
+}
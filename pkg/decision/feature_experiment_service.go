@@ -46,6 +46,13 @@ func (f FeatureExperimentService) GetDecision(decisionContext FeatureDecisionCon
 	// @TODO this can be improved by getting group ID first and determining experiment and then bucketing in experiment
 	for _, featureExperiment := range feature.FeatureExperiments {
 
+		// Skipping feature tests that are not running, forced decisions do not apply to them either
+		if !featureExperiment.IsRunning() {
+			logMessage := reasons.AddInfo(logging.ExperimentNotRunning.String(), featureExperiment.Key, featureExperiment.Status)
+			f.logger.Info(logMessage)
+			continue
+		}
+
 		// Checking for forced decision
 		if decisionContext.ForcedDecisionService != nil {
 			forcedDecision, _reasons, err := decisionContext.ForcedDecisionService.FindValidatedForcedDecision(decisionContext.ProjectConfig, OptimizelyDecisionContext{FlagKey: feature.Key, RuleKey: featureExperiment.Key}, options)
@@ -132,6 +132,30 @@ func (s *FeatureExperimentServiceTestSuite) TestGetDecisionWithForcedDecision()
 	s.mockExperimentService.AssertExpectations(s.T())
 }
 
+func (s *FeatureExperimentServiceTestSuite) TestGetDecisionWithForcedDecisionNotRunning() {
+	testUserContext := entities.UserContext{
+		ID: "test_user_1",
+	}
+	pausedExperiment := testExp1113
+	pausedExperiment.Status = entities.ExperimentStatusPaused
+	feature := testFeat3335
+	feature.FeatureExperiments = []entities.Experiment{pausedExperiment}
+	s.testFeatureDecisionContext.Feature = &feature
+	s.testFeatureDecisionContext.ForcedDecisionService.SetForcedDecision(OptimizelyDecisionContext{FlagKey: feature.Key, RuleKey: pausedExperiment.Key}, OptimizelyForcedDecision{VariationKey: "2223"})
+
+	featureExperimentService := &FeatureExperimentService{
+		compositeExperimentService: s.mockExperimentService,
+		logger:                     logging.GetLogger("sdkKey", "FeatureExperimentService"),
+	}
+	options := &decide.Options{IncludeReasons: true}
+	decision, reasons, err := featureExperimentService.GetDecision(s.testFeatureDecisionContext, testUserContext, options)
+	s.NoError(err)
+	s.Equal(FeatureDecision{}, decision)
+	s.Equal([]string{`Experiment "test_experiment_1113" is not running (status "Paused").`}, reasons.ToReport())
+	s.mockConfig.AssertNotCalled(s.T(), "GetFlagVariationsMap")
+	s.mockExperimentService.AssertNotCalled(s.T(), "GetDecision")
+}
+
 func (s *FeatureExperimentServiceTestSuite) TestGetDecisionMutex() {
 	testUserContext := entities.UserContext{
 		ID: "test_user_1",
@@ -49,6 +49,11 @@ func (c *mockProjectConfig) GetAudienceByID(audienceID string) (entities.Audienc
 	return args.Get(0).(entities.Audience), args.Error(1)
 }
 
+func (c *mockProjectConfig) GetGroupByID(groupID string) (entities.Group, error) {
+	args := c.Called(groupID)
+	return args.Get(0).(entities.Group), args.Error(1)
+}
+
 func (c *mockProjectConfig) GetAudienceMap() map[string]entities.Audience {
 	args := c.Called()
 	return args.Get(0).(map[string]entities.Audience)
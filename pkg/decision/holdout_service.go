@@ -0,0 +1,88 @@
+/****************************************************************************
+ * Copyright 2026, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package decision //
+package decision
+
+import (
+	"github.com/WolffunService/experiment/pkg/decide"
+	"github.com/WolffunService/experiment/pkg/decision/bucketer"
+	pkgReasons "github.com/WolffunService/experiment/pkg/decision/reasons"
+	"github.com/WolffunService/experiment/pkg/entities"
+	"github.com/WolffunService/experiment/pkg/logging"
+)
+
+// HoldoutService makes a feature decision for users in a global holdout, who are excluded from every feature test and
+// rollout and get the flag defaults
+type HoldoutService struct {
+	experimentBucketerService ExperimentService
+	logger                    logging.OptimizelyLogProducer
+}
+
+// HSOptionFunc is used to assign optional configuration options to the HoldoutService
+type HSOptionFunc func(*HoldoutService)
+
+// WithHoldoutBucketer sets the experiment bucketer used to bucket users into holdouts, instead of the default
+// murmurhash one
+func WithHoldoutBucketer(experimentBucketer bucketer.ExperimentBucketer) HSOptionFunc {
+	return func(h *HoldoutService) {
+		if experimentBucketerService, ok := h.experimentBucketerService.(*ExperimentBucketerService); ok {
+			experimentBucketerService.bucketer = experimentBucketer
+		}
+	}
+}
+
+// NewHoldoutService returns a new instance of the HoldoutService
+func NewHoldoutService(sdkKey string, options ...HSOptionFunc) *HoldoutService {
+	holdoutService := &HoldoutService{
+		logger:                    logging.GetLogger(sdkKey, "HoldoutService"),
+		experimentBucketerService: NewExperimentBucketerService(logging.GetLogger(sdkKey, "ExperimentBucketerService")),
+	}
+	for _, opt := range options {
+		opt(holdoutService)
+	}
+	return holdoutService
+}
+
+// GetDecision returns the holdout decision if the user is targeted by and bucketed into a running global holdout
+func (h HoldoutService) GetDecision(decisionContext FeatureDecisionContext, userContext entities.UserContext, options *decide.Options) (FeatureDecision, decide.DecisionReasons, error) {
+	reasons := decide.NewDecisionReasons(options)
+	for _, holdout := range decisionContext.ProjectConfig.GetHoldoutList() {
+		if !holdout.IsRunning() {
+			continue
+		}
+		holdout := holdout
+		experimentDecisionContext := ExperimentDecisionContext{
+			Experiment:    &holdout,
+			ProjectConfig: decisionContext.ProjectConfig,
+		}
+		decision, decisionReasons, _ := h.experimentBucketerService.GetDecision(experimentDecisionContext, userContext, options)
+		reasons.Append(decisionReasons)
+		if decision.Variation == nil {
+			continue
+		}
+
+		logMessage := reasons.AddInfo(`User "%s" is in holdout "%s".`, userContext.ID, holdout.Key)
+		h.logger.Debug(logMessage)
+		return FeatureDecision{
+			Decision:   Decision{Reason: pkgReasons.InHoldout},
+			Source:     Holdout,
+			Experiment: holdout,
+			Variation:  decision.Variation,
+		}, reasons, nil
+	}
+	return FeatureDecision{}, reasons, nil
+}
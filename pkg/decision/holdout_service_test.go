@@ -0,0 +1,112 @@
+/****************************************************************************
+ * Copyright 2026, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+package decision
+
+import (
+	"testing"
+
+	"github.com/WolffunService/experiment/pkg/config"
+	"github.com/WolffunService/experiment/pkg/config/datafilebuilder"
+	"github.com/WolffunService/experiment/pkg/config/datafileprojectconfig"
+	"github.com/WolffunService/experiment/pkg/decide"
+	"github.com/WolffunService/experiment/pkg/decision/reasons"
+	"github.com/WolffunService/experiment/pkg/entities"
+	"github.com/WolffunService/experiment/pkg/logging"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newHoldoutDecisionContext(t *testing.T, builder *datafilebuilder.Builder) FeatureDecisionContext {
+	builder.Attribute("country").Audience("vn", []interface{}{"and", map[string]interface{}{"type": "custom_attribute", "name": "country", "value": "vn"}})
+	builder.Flag("flag_1").Rule("everyone")
+	projectConfig, err := datafileprojectconfig.NewDatafileProjectConfig(builder.MustBuild(), logging.GetLogger("", "DatafileProjectConfig"))
+	assert.NoError(t, err)
+	feature, err := projectConfig.GetFeatureByKey("flag_1")
+	assert.NoError(t, err)
+	return FeatureDecisionContext{Feature: &feature, ProjectConfig: projectConfig}
+}
+
+func TestHoldoutServiceGetDecision(t *testing.T) {
+	builder := datafilebuilder.New().Holdout("global_holdout", 100)
+	decisionContext := newHoldoutDecisionContext(t, builder)
+	options := &decide.Options{IncludeReasons: true}
+
+	decision, decisionReasons, err := NewHoldoutService("").GetDecision(decisionContext, entities.UserContext{ID: "user_1"}, options)
+	assert.NoError(t, err)
+	assert.Equal(t, Holdout, decision.Source)
+	assert.Equal(t, reasons.InHoldout, decision.Reason)
+	assert.Equal(t, "global_holdout", decision.Experiment.Key)
+	assert.Equal(t, "off", decision.Variation.Key)
+	assert.False(t, decision.Variation.FeatureEnabled)
+	assert.Contains(t, decisionReasons.ToReport(), `User "user_1" is in holdout "global_holdout".`)
+}
+
+func TestHoldoutServiceGetDecisionOutsideHoldout(t *testing.T) {
+	builder := datafilebuilder.New().Holdout("empty_holdout", 0).Holdout("targeted_holdout", 100, "vn")
+	decisionContext := newHoldoutDecisionContext(t, builder)
+	holdoutService := NewHoldoutService("")
+
+	// users outside the traffic or the audiences of every holdout fall through to the other feature services
+	decision, _, err := holdoutService.GetDecision(decisionContext, entities.UserContext{ID: "user_1", Attributes: map[string]interface{}{"country": "us"}}, &decide.Options{})
+	assert.NoError(t, err)
+	assert.Nil(t, decision.Variation)
+	assert.Equal(t, Source(""), decision.Source)
+
+	decision, _, err = holdoutService.GetDecision(decisionContext, entities.UserContext{ID: "user_1", Attributes: map[string]interface{}{"country": "vn"}}, &decide.Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, "targeted_holdout", decision.Experiment.Key)
+}
+
+func TestHoldoutServiceSkipsHoldoutsNotRunning(t *testing.T) {
+	decisionContext := newHoldoutDecisionContext(t, datafilebuilder.New())
+	holdout := entities.Experiment{
+		ID:                "1",
+		Key:               "paused_holdout",
+		Status:            entities.ExperimentStatusPaused,
+		Variations:        map[string]entities.Variation{"2": {ID: "2", Key: "off"}},
+		TrafficAllocation: []entities.Range{{EntityID: "2", EndOfRange: 10000}},
+	}
+	decisionContext.ProjectConfig = &holdoutProjectConfig{ProjectConfig: decisionContext.ProjectConfig, holdouts: []entities.Experiment{holdout}}
+
+	decision, _, err := NewHoldoutService("").GetDecision(decisionContext, entities.UserContext{ID: "user_1"}, &decide.Options{})
+	assert.NoError(t, err)
+	assert.Nil(t, decision.Variation)
+}
+
+func TestHoldoutServiceWithHoldoutBucketer(t *testing.T) {
+	// the default bucketer never puts anyone into a holdout without traffic, the configured one does
+	decisionContext := newHoldoutDecisionContext(t, datafilebuilder.New().Holdout("empty_holdout", 0))
+	holdout := decisionContext.ProjectConfig.GetHoldoutList()[0]
+	variation := holdout.Variations[holdout.TrafficAllocation[0].EntityID]
+	mockBucketer := new(MockBucketer)
+	mockBucketer.On("Bucket", "user_1", holdout, entities.Group{}).Return(&variation, reasons.BucketedIntoVariation, nil)
+
+	decision, _, err := NewHoldoutService("", WithHoldoutBucketer(mockBucketer)).GetDecision(decisionContext, entities.UserContext{ID: "user_1"}, &decide.Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, Holdout, decision.Source)
+	assert.Equal(t, "empty_holdout", decision.Experiment.Key)
+	mockBucketer.AssertExpectations(t)
+}
+
+type holdoutProjectConfig struct {
+	config.ProjectConfig
+	holdouts []entities.Experiment
+}
+
+func (c *holdoutProjectConfig) GetHoldoutList() []entities.Experiment {
+	return c.holdouts
+}
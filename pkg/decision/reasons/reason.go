@@ -27,6 +27,8 @@ const (
 	BucketedVariationNotFound Reason = "Bucketed variation not found"
 	// BucketedIntoVariation - the user is bucketed into a variation for the given experiment
 	BucketedIntoVariation Reason = "Bucketed into variation"
+	// BanditAllocatedVariation - the user is allocated to a variation of a bandit experiment
+	BanditAllocatedVariation Reason = "Allocated into variation by bandit"
 	// BucketedIntoFeatureTest - the user is bucketed into a variation for the given feature test
 	BucketedIntoFeatureTest Reason = "Bucketed into feature test"
 	// BucketedIntoRollout - the user is bucketed into a variation for the given feature rollout
@@ -37,6 +39,10 @@ const (
 	FailedRolloutTargeting Reason = "Does not meet rollout targeting rule"
 	// FailedAudienceTargeting - the user failed the audience targeting conditions
 	FailedAudienceTargeting Reason = "Does not meet audience targeting conditions"
+	// ExperimentNotRunning - the experiment is not running so the user is not bucketed into it
+	ExperimentNotRunning Reason = "Experiment is not running"
+	// InHoldout - the user is in a global holdout and gets the flag defaults
+	InHoldout Reason = "In global holdout"
 	// NoRolloutForFeature - there is no rollout for the given feature
 	NoRolloutForFeature Reason = "No rollout for feature"
 	// RolloutHasNoExperiments - the rollout has no assigned experiments
@@ -45,6 +51,10 @@ const (
 	ForcedDecisionFound Reason = "Forced decision found"
 	// NotBucketedIntoVariation - the user is not bucketed into a variation for the given experiment
 	NotBucketedIntoVariation Reason = "Not bucketed into a variation"
+	// UnknownGroupPolicy - the experiment's group uses a policy that has not been registered with the bucketer
+	UnknownGroupPolicy Reason = "Unknown group policy"
+	// NotInRamp - the ramp schedule of the experiment doesn't let the user in yet
+	NotInRamp Reason = "Not yet let in by the ramp schedule"
 	// NotInGroup - the user is not bucketed into the mutex group
 	NotInGroup Reason = "Not bucketed into any experiment in mutex group"
 	// NoWhitelistVariationAssignment - there is no variation assignment for the given user and experiment
@@ -22,12 +22,26 @@ import (
 	"strconv"
 
 	"github.com/WolffunService/experiment/pkg/decide"
+	"github.com/WolffunService/experiment/pkg/decision/bucketer"
 	"github.com/WolffunService/experiment/pkg/decision/evaluator"
 	pkgReasons "github.com/WolffunService/experiment/pkg/decision/reasons"
 	"github.com/WolffunService/experiment/pkg/entities"
 	"github.com/WolffunService/experiment/pkg/logging"
 )
 
+// RSOptionFunc is used to assign optional configuration options to the RolloutService
+type RSOptionFunc func(*RolloutService)
+
+// WithRolloutBucketer sets the experiment bucketer used to bucket users into rollout rules, instead of the default
+// murmurhash one
+func WithRolloutBucketer(experimentBucketer bucketer.ExperimentBucketer) RSOptionFunc {
+	return func(r *RolloutService) {
+		if experimentBucketerService, ok := r.experimentBucketerService.(*ExperimentBucketerService); ok {
+			experimentBucketerService.bucketer = experimentBucketer
+		}
+	}
+}
+
 // RolloutService makes a feature decision for a given feature rollout
 type RolloutService struct {
 	audienceTreeEvaluator     evaluator.TreeEvaluator
@@ -36,13 +50,17 @@ type RolloutService struct {
 }
 
 // NewRolloutService returns a new instance of the Rollout service
-func NewRolloutService(sdkKey string) *RolloutService {
+func NewRolloutService(sdkKey string, options ...RSOptionFunc) *RolloutService {
 	logger := logging.GetLogger(sdkKey, "RolloutService")
-	return &RolloutService{
+	rolloutService := &RolloutService{
 		logger:                    logger,
 		audienceTreeEvaluator:     evaluator.NewMixedTreeEvaluator(logger),
 		experimentBucketerService: NewExperimentBucketerService(logging.GetLogger(sdkKey, "ExperimentBucketerService")),
 	}
+	for _, opt := range options {
+		opt(rolloutService)
+	}
+	return rolloutService
 }
 
 // GetDecision returns a decision for the given feature and user context
@@ -84,6 +102,15 @@ func (r RolloutService) GetDecision(decisionContext FeatureDecisionContext, user
 		return featureDecision, reasons, nil
 	}
 
+	isRuleRunning := func(experiment *entities.Experiment) bool {
+		if experiment.IsRunning() {
+			return true
+		}
+		logMessage := reasons.AddInfo(logging.ExperimentNotRunning.String(), experiment.Key, experiment.Status)
+		r.logger.Info(logMessage)
+		return false
+	}
+
 	checkForForcedDecision := func(exp *entities.Experiment) *FeatureDecision {
 		forcedDecision, _reasons := r.getForcedDecision(decisionContext, *exp, options)
 		reasons.Append(_reasons)
@@ -102,6 +129,11 @@ func (r RolloutService) GetDecision(decisionContext FeatureDecisionContext, user
 		loggingKey := strconv.Itoa(index + 1)
 		experiment := &rollout.Experiments[index]
 
+		// Move to next evaluation if the rule is not running, forced decisions do not apply to it either
+		if !isRuleRunning(experiment) {
+			continue
+		}
+
 		// Checking for forced decision
 		if forcedDecision := checkForForcedDecision(experiment); forcedDecision != nil {
 			return *forcedDecision, reasons, nil
@@ -132,6 +164,11 @@ func (r RolloutService) GetDecision(decisionContext FeatureDecisionContext, user
 	// fall back rule / last rule
 	experiment := &rollout.Experiments[numberOfExperiments-1]
 
+	if !isRuleRunning(experiment) {
+		featureDecision.Reason = pkgReasons.ExperimentNotRunning
+		return featureDecision, reasons, nil
+	}
+
 	// Checking for forced decision
 	if forcedDecision := checkForForcedDecision(experiment); forcedDecision != nil {
 		return *forcedDecision, reasons, nil
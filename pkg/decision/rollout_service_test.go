@@ -381,12 +381,125 @@ func (s *RolloutServiceTestSuite) TestGetDecisionFailsTargeting() {
 	s.mockLogger.AssertExpectations(s.T())
 }
 
+func (s *RolloutServiceTestSuite) TestSkipsRuleWhenNotRunning() {
+	pausedExperiment := testExp1112
+	pausedExperiment.Status = entities.ExperimentStatusPaused
+	feature := testFeatRollout3334
+	feature.Rollout.Experiments = []entities.Experiment{pausedExperiment, testExp1117, testExp1118}
+	featureDecisionContext := FeatureDecisionContext{
+		Feature:       &feature,
+		ProjectConfig: s.mockConfig,
+	}
+	s.mockAudienceTreeEvaluator.On("Evaluate", testExp1117.AudienceConditionTree, s.testConditionTreeParams, mock.Anything).Return(true, true, s.reasons)
+
+	experiment1117DecisionContext := ExperimentDecisionContext{
+		Experiment:    &feature.Rollout.Experiments[1],
+		ProjectConfig: s.mockConfig,
+	}
+	testExperimentBucketerDecision := ExperimentDecision{
+		Variation: &testExp1117Var2223,
+		Decision:  Decision{Reason: reasons.BucketedIntoVariation},
+	}
+	s.mockExperimentService.On("GetDecision", experiment1117DecisionContext, s.testUserContext, s.options, mock.Anything).Return(testExperimentBucketerDecision, s.reasons, nil)
+
+	testRolloutService := RolloutService{
+		audienceTreeEvaluator:     s.mockAudienceTreeEvaluator,
+		experimentBucketerService: s.mockExperimentService,
+		logger:                    s.mockLogger,
+	}
+	expectedFeatureDecision := FeatureDecision{
+		Experiment: testExp1117,
+		Variation:  &testExp1117Var2223,
+		Source:     Rollout,
+		Decision:   Decision{Reason: reasons.BucketedIntoRollout},
+	}
+	s.mockLogger.On("Info", fmt.Sprintf(logging.ExperimentNotRunning.String(), pausedExperiment.Key, entities.ExperimentStatusPaused))
+	s.mockLogger.On("Debug", fmt.Sprintf(logging.EvaluatingAudiencesForRollout.String(), "2"))
+	s.mockLogger.On("Debug", fmt.Sprintf(logging.RolloutAudiencesEvaluatedTo.String(), "2", true))
+	s.mockLogger.On("Debug", `Decision made for user "test_user" for feature rollout with key "test_feature_rollout_3334_key": Bucketed into feature rollout.`)
+	s.options.IncludeReasons = true
+	decision, rsons, _ := testRolloutService.GetDecision(featureDecisionContext, s.testUserContext, s.options)
+	messages := rsons.ToReport()
+	s.Len(messages, 1)
+	s.Equal(fmt.Sprintf(`Experiment "%s" is not running (status "Paused").`, pausedExperiment.Key), messages[0])
+
+	s.Equal(expectedFeatureDecision, decision)
+	s.mockAudienceTreeEvaluator.AssertNotCalled(s.T(), "Evaluate", testExp1112.AudienceConditionTree, s.testConditionTreeParams, mock.Anything)
+	s.mockExperimentService.AssertExpectations(s.T())
+	s.mockLogger.AssertExpectations(s.T())
+}
+
+func (s *RolloutServiceTestSuite) TestGetDecisionWhenFallbackRuleNotRunning() {
+	archivedExperiment := testExp1118
+	archivedExperiment.Status = entities.ExperimentStatusArchived
+	feature := testFeatRollout3334
+	feature.Rollout.Experiments = []entities.Experiment{archivedExperiment}
+	featureDecisionContext := FeatureDecisionContext{
+		Feature:       &feature,
+		ProjectConfig: s.mockConfig,
+	}
+	testRolloutService := RolloutService{
+		audienceTreeEvaluator:     s.mockAudienceTreeEvaluator,
+		experimentBucketerService: s.mockExperimentService,
+		logger:                    s.mockLogger,
+	}
+	expectedFeatureDecision := FeatureDecision{
+		Source:   Rollout,
+		Decision: Decision{Reason: reasons.ExperimentNotRunning},
+	}
+	s.mockLogger.On("Info", fmt.Sprintf(logging.ExperimentNotRunning.String(), archivedExperiment.Key, entities.ExperimentStatusArchived))
+	decision, _, _ := testRolloutService.GetDecision(featureDecisionContext, s.testUserContext, s.options)
+	s.Equal(expectedFeatureDecision, decision)
+	s.mockAudienceTreeEvaluator.AssertNotCalled(s.T(), "Evaluate")
+	s.mockExperimentService.AssertNotCalled(s.T(), "GetDecision")
+	s.mockLogger.AssertExpectations(s.T())
+}
+
+func (s *RolloutServiceTestSuite) TestForcedDecisionIgnoredWhenRuleNotRunning() {
+	pausedExperiment := testExp1112
+	pausedExperiment.Status = entities.ExperimentStatusPaused
+	pausedFallbackExperiment := testExp1118
+	pausedFallbackExperiment.Status = entities.ExperimentStatusPaused
+	feature := testFeatRollout3334
+	feature.Rollout.Experiments = []entities.Experiment{pausedExperiment, pausedFallbackExperiment}
+	featureDecisionContext := FeatureDecisionContext{
+		Feature:               &feature,
+		ProjectConfig:         s.mockConfig,
+		ForcedDecisionService: NewForcedDecisionService("test_user"),
+	}
+	featureDecisionContext.ForcedDecisionService.SetForcedDecision(OptimizelyDecisionContext{FlagKey: feature.Key, RuleKey: pausedExperiment.Key}, OptimizelyForcedDecision{VariationKey: testExp1112Var2222.Key})
+	featureDecisionContext.ForcedDecisionService.SetForcedDecision(OptimizelyDecisionContext{FlagKey: feature.Key, RuleKey: pausedFallbackExperiment.Key}, OptimizelyForcedDecision{VariationKey: testExp1118Var2224.Key})
+	testRolloutService := RolloutService{
+		audienceTreeEvaluator:     s.mockAudienceTreeEvaluator,
+		experimentBucketerService: s.mockExperimentService,
+		logger:                    s.mockLogger,
+	}
+	expectedFeatureDecision := FeatureDecision{
+		Source:   Rollout,
+		Decision: Decision{Reason: reasons.ExperimentNotRunning},
+	}
+	s.mockLogger.On("Info", fmt.Sprintf(logging.ExperimentNotRunning.String(), pausedExperiment.Key, entities.ExperimentStatusPaused))
+	s.mockLogger.On("Info", fmt.Sprintf(logging.ExperimentNotRunning.String(), pausedFallbackExperiment.Key, entities.ExperimentStatusPaused))
+	decision, _, _ := testRolloutService.GetDecision(featureDecisionContext, s.testUserContext, s.options)
+	s.Equal(expectedFeatureDecision, decision)
+	s.mockConfig.AssertNotCalled(s.T(), "GetFlagVariationsMap")
+	s.mockExperimentService.AssertNotCalled(s.T(), "GetDecision")
+	s.mockLogger.AssertExpectations(s.T())
+}
+
 func TestNewRolloutService(t *testing.T) {
 	rolloutService := NewRolloutService("")
 	assert.IsType(t, &evaluator.MixedTreeEvaluator{}, rolloutService.audienceTreeEvaluator)
 	assert.IsType(t, &ExperimentBucketerService{logger: logging.GetLogger("sdkKey", "ExperimentBucketerService")}, rolloutService.experimentBucketerService)
 }
 
+func TestNewRolloutServiceWithRolloutBucketer(t *testing.T) {
+	mockBucketer := new(MockBucketer)
+	rolloutService := NewRolloutService("", WithRolloutBucketer(mockBucketer))
+	experimentBucketerService := rolloutService.experimentBucketerService.(*ExperimentBucketerService)
+	assert.Equal(t, mockBucketer, experimentBucketerService.bucketer)
+}
+
 func TestRolloutServiceTestSuite(t *testing.T) {
 	suite.Run(t, new(RolloutServiceTestSuite))
 }
@@ -17,6 +17,8 @@
 // Package entities //
 package entities
 
+import "time"
+
 // Variation represents a variation in the experiment
 type Variation struct {
 	ID             string
@@ -25,6 +27,39 @@ type Variation struct {
 	FeatureEnabled bool
 }
 
+// ExperimentStatus represents the lifecycle status of an experiment
+type ExperimentStatus string
+
+const (
+	// ExperimentStatusRunning - the experiment is running and buckets users
+	ExperimentStatusRunning ExperimentStatus = "Running"
+	// ExperimentStatusLaunched - the experiment has been launched and buckets users
+	ExperimentStatusLaunched ExperimentStatus = "Launched"
+	// ExperimentStatusPaused - the experiment is paused and does not bucket users
+	ExperimentStatusPaused ExperimentStatus = "Paused"
+	// ExperimentStatusArchived - the experiment is archived and does not bucket users
+	ExperimentStatusArchived ExperimentStatus = "Archived"
+	// ExperimentStatusNotStarted - the experiment has not been started yet and does not bucket users
+	ExperimentStatusNotStarted ExperimentStatus = "Not started"
+)
+
+// BanditAlgorithm is the algorithm a bandit experiment allocates traffic by
+type BanditAlgorithm string
+
+const (
+	// BanditThompsonSampling - allocate each user to the variation winning a draw from the conversion rate posteriors
+	BanditThompsonSampling BanditAlgorithm = "thompson_sampling"
+	// BanditEpsilonGreedy - allocate to the best converting variation, or to a random one with probability epsilon
+	BanditEpsilonGreedy BanditAlgorithm = "epsilon_greedy"
+)
+
+// Bandit holds the metadata of an experiment that allocates its traffic as a multi-armed bandit
+type Bandit struct {
+	Algorithm BanditAlgorithm
+	Epsilon   float64
+	EventKey  string // the conversion event, defaults to the events listing the experiment
+}
+
 // Experiment represents an experiment
 type Experiment struct {
 	AudienceIds           []string
@@ -40,6 +75,43 @@ type Experiment struct {
 	Whitelist             map[string]string
 	IsFeatureExperiment   bool
 	Revision              int
+	BucketByRevision      *bool // nil leaves it to the bucketer
+	Bandit                *Bandit
+	RampSchedule          []RampStep // ordered by start time
+	Status                ExperimentStatus
+}
+
+// RampStep lets the given percentage of traffic into an experiment from its start time on
+type RampStep struct {
+	StartTime  time.Time
+	Percentage float64
+}
+
+// RampPercentage returns the percentage of traffic the ramp schedule lets into the experiment at the given time.
+// Experiments without a schedule let all their traffic in, and none is let in before the first step starts.
+func (e Experiment) RampPercentage(at time.Time) float64 {
+	if len(e.RampSchedule) == 0 {
+		return 100
+	}
+	percentage := 0.0
+	for _, step := range e.RampSchedule {
+		if step.StartTime.After(at) {
+			break
+		}
+		percentage = step.Percentage
+	}
+	return percentage
+}
+
+// IsRunning returns true if users can be bucketed into the experiment.
+// An empty status is treated as running to stay compatible with datafiles that omit it.
+func (e Experiment) IsRunning() bool {
+	switch e.Status {
+	case "", ExperimentStatusRunning, ExperimentStatusLaunched:
+		return true
+	default:
+		return false
+	}
 }
 
 // Range represents bucketing range that the specify entityID falls into
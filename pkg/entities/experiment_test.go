@@ -0,0 +1,49 @@
+/****************************************************************************
+ * Copyright 2026, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package entities //
+package entities
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExperimentIsRunning(t *testing.T) {
+	assert.True(t, Experiment{}.IsRunning())
+	assert.True(t, Experiment{Status: ExperimentStatusRunning}.IsRunning())
+	assert.True(t, Experiment{Status: ExperimentStatusLaunched}.IsRunning())
+	assert.False(t, Experiment{Status: ExperimentStatusPaused}.IsRunning())
+	assert.False(t, Experiment{Status: ExperimentStatusArchived}.IsRunning())
+	assert.False(t, Experiment{Status: ExperimentStatusNotStarted}.IsRunning())
+}
+
+func TestExperimentRampPercentage(t *testing.T) {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	experiment := Experiment{RampSchedule: []RampStep{
+		{StartTime: start, Percentage: 1},
+		{StartTime: start.Add(24 * time.Hour), Percentage: 5},
+		{StartTime: start.Add(72 * time.Hour), Percentage: 25},
+	}}
+
+	assert.Equal(t, 0.0, experiment.RampPercentage(start.Add(-time.Second)))
+	assert.Equal(t, 1.0, experiment.RampPercentage(start))
+	assert.Equal(t, 5.0, experiment.RampPercentage(start.Add(48*time.Hour)))
+	assert.Equal(t, 25.0, experiment.RampPercentage(start.Add(30*24*time.Hour)))
+	assert.Equal(t, 100.0, Experiment{}.RampPercentage(start))
+}
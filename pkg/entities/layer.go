@@ -0,0 +1,40 @@
+/****************************************************************************
+ * Copyright 2026, Optimizely, Inc. and contributors                        *
+ *                                                                          *
+ * Licensed under the Apache License, Version 2.0 (the "License");          *
+ * you may not use this file except in compliance with the License.         *
+ * You may obtain a copy of the License at                                  *
+ *                                                                          *
+ *    http://www.apache.org/licenses/LICENSE-2.0                            *
+ *                                                                          *
+ * Unless required by applicable law or agreed to in writing, software      *
+ * distributed under the License is distributed on an "AS IS" BASIS,        *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. *
+ * See the License for the specific language governing permissions and      *
+ * limitations under the License.                                           *
+ ***************************************************************************/
+
+// Package entities //
+package entities
+
+// LayerPolicy is the policy of the groups layers are bucketed as
+const LayerPolicy = "random"
+
+// Layer partitions the experiments referencing it through their LayerID: they are mutually exclusive within the layer
+// and orthogonal to the experiments of any other layer
+type Layer struct {
+	ID                string
+	Key               string
+	Salt              string
+	TrafficAllocation []Range
+}
+
+// ExclusionGroup returns the layer as a random policy group whose ID, which the bucketing key is hashed with, is the
+// layer salt
+func (l Layer) ExclusionGroup() Group {
+	return Group{
+		ID:                l.Salt,
+		TrafficAllocation: l.TrafficAllocation,
+		Policy:            LayerPolicy,
+	}
+}
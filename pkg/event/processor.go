@@ -54,6 +54,8 @@ type BatchEventProcessor struct {
 	processing      *semaphore.Weighted
 	logger          logging.OptimizelyLogProducer
 	metricsRegistry metrics.Registry
+
+	notificationCenter notification.Center
 }
 
 // DefaultBatchSize holds the default value for the batch size
@@ -130,6 +132,14 @@ func WithEventDispatcherMetrics(metricsRegistry metrics.Registry) BPOptionConfig
 	}
 }
 
+// WithNotificationCenter sets the notification center LogEvent notifications are sent to, instead of the one the
+// registry holds for the SDK key
+func WithNotificationCenter(notificationCenter notification.Center) BPOptionConfig {
+	return func(qp *BatchEventProcessor) {
+		qp.notificationCenter = notificationCenter
+	}
+}
+
 // NewBatchEventProcessor returns a new instance of BatchEventProcessor with queueSize and flushInterval
 func NewBatchEventProcessor(options ...BPOptionConfig) *BatchEventProcessor {
 	p := &BatchEventProcessor{processing: semaphore.NewWeighted(int64(maxFlushWorkers))}
@@ -140,6 +150,10 @@ func NewBatchEventProcessor(options ...BPOptionConfig) *BatchEventProcessor {
 
 	p.logger = logging.GetLogger(p.sdkKey, "BatchEventProcessor")
 
+	if p.notificationCenter == nil {
+		p.notificationCenter = registry.GetNotificationCenter(p.sdkKey)
+	}
+
 	if p.MaxQueueSize == 0 {
 		p.MaxQueueSize = defaultQueueSize
 	}
@@ -313,9 +327,7 @@ func (p *BatchEventProcessor) flushEvents() {
 		if batchEventCount > 0 {
 			// TODO: figure out what to do with the error
 			logEvent := createLogEvent(batchEvent, p.EventEndPoint)
-			notificationCenter := registry.GetNotificationCenter(p.sdkKey)
-
-			err := notificationCenter.Send(notification.LogEvent, logEvent)
+			err := p.getNotificationCenter().Send(notification.LogEvent, logEvent)
 
 			if err != nil {
 				p.logger.Error("Send Log Event notification failed.", err)
@@ -335,7 +347,7 @@ func (p *BatchEventProcessor) flushEvents() {
 
 // OnEventDispatch registers a handler for LogEvent notifications
 func (p *BatchEventProcessor) OnEventDispatch(callback func(logEvent LogEvent)) (int, error) {
-	notificationCenter := registry.GetNotificationCenter(p.sdkKey)
+	notificationCenter := p.getNotificationCenter()
 
 	handler := func(payload interface{}) {
 		if ev, ok := payload.(LogEvent); ok {
@@ -354,7 +366,7 @@ func (p *BatchEventProcessor) OnEventDispatch(callback func(logEvent LogEvent))
 
 // RemoveOnEventDispatch removes handler for LogEvent notification with given id
 func (p *BatchEventProcessor) RemoveOnEventDispatch(id int) error {
-	notificationCenter := registry.GetNotificationCenter(p.sdkKey)
+	notificationCenter := p.getNotificationCenter()
 
 	if err := notificationCenter.RemoveHandler(id, notification.LogEvent); err != nil {
 		p.logger.Warning("Problem with removing notification handler.")
@@ -362,3 +374,11 @@ func (p *BatchEventProcessor) RemoveOnEventDispatch(id int) error {
 	}
 	return nil
 }
+
+// getNotificationCenter falls back to the registry for processors which were not created with NewBatchEventProcessor
+func (p *BatchEventProcessor) getNotificationCenter() notification.Center {
+	if p.notificationCenter == nil {
+		return registry.GetNotificationCenter(p.sdkKey)
+	}
+	return p.notificationCenter
+}
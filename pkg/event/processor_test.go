@@ -28,6 +28,8 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/WolffunService/experiment/pkg/logging"
+	"github.com/WolffunService/experiment/pkg/notification"
+	"github.com/WolffunService/experiment/pkg/registry"
 	"github.com/WolffunService/experiment/pkg/utils"
 )
 
@@ -150,6 +152,26 @@ func TestDefaultEventProcessor_LogEventNotification(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestBatchEventProcessor_WithNotificationCenter(t *testing.T) {
+	notificationCenter := notification.NewNotificationCenter()
+	processor := NewBatchEventProcessor(WithSDKKey("notification_center_sdk_key"), WithNotificationCenter(notificationCenter))
+
+	var logEvents []LogEvent
+	id, err := processor.OnEventDispatch(func(logEvent LogEvent) {
+		logEvents = append(logEvents, logEvent)
+	})
+	assert.NoError(t, err)
+
+	registry.GetNotificationCenter("notification_center_sdk_key").Send(notification.LogEvent, LogEvent{})
+	assert.Empty(t, logEvents)
+	notificationCenter.Send(notification.LogEvent, LogEvent{EndPoint: "endpoint"})
+	assert.Equal(t, []LogEvent{{EndPoint: "endpoint"}}, logEvents)
+
+	assert.NoError(t, processor.RemoveOnEventDispatch(id))
+	notificationCenter.Send(notification.LogEvent, LogEvent{})
+	assert.Len(t, logEvents, 1)
+}
+
 func TestDefaultEventProcessor_BatchSizes(t *testing.T) {
 	eg := newExecutionContext()
 	processor := NewBatchEventProcessor(
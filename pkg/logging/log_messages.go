@@ -47,6 +47,10 @@ const (
 	UserNotInRollout LogMessage = `User "%s" does not meet conditions for targeting rule %s.`
 	// UserNotInExperiment when user is not in experiment
 	UserNotInExperiment LogMessage = `User "%s" does not meet conditions to be in experiment "%s".`
+	// UserNotInRamp when the ramp schedule of the experiment doesn't let the user in yet
+	UserNotInRamp LogMessage = `User "%s" is not yet let into experiment "%s" by its ramp schedule.`
+	// ExperimentNotRunning when experiment is paused, archived or not started
+	ExperimentNotRunning LogMessage = `Experiment "%s" is not running (status "%s").`
 
 	// Warning logs
 
@@ -58,4 +62,6 @@ const (
 	UnsupportedConditionValue LogMessage = `Audience condition "%s" has an unsupported condition value. You may need to upgrade to a newer release of the Optimizely SDK.`
 	// InvalidAttributeValueType when user attribute value is invalid
 	InvalidAttributeValueType LogMessage = `Audience condition "%s" evaluated to UNKNOWN because a value of type "%T" was passed for user attribute "%s".`
+	// UnknownGroupPolicy when the group of an experiment uses a policy the bucketer doesn't know
+	UnknownGroupPolicy LogMessage = `Group "%s" of experiment "%s" uses unknown policy "%s", so the user is not bucketed.`
 )
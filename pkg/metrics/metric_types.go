@@ -24,3 +24,9 @@ const (
 	DispatcherRetryFlush   = "dispatcher.retryFlush"
 	DispatcherQueueSize    = "dispatcher.queueSize"
 )
+
+// DatafileCompressedBytes stores the size of the compressed datafiles received
+const (
+	DatafileCompressedBytes   = "datafile.compressedBytes"
+	DatafileDecompressedBytes = "datafile.decompressedBytes"
+)
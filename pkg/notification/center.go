@@ -40,11 +40,13 @@ func NewNotificationCenter() *DefaultCenter {
 	projectConfigUpdateNotificationManager := NewAtomicManager(logging.GetLogger("", "AtomicManager"))
 	processLogEventNotificationManager := NewAtomicManager(logging.GetLogger("", "AtomicManager"))
 	trackNotificationManager := NewAtomicManager(logging.GetLogger("", "AtomicManager"))
+	datafileRejectedNotificationManager := NewAtomicManager(logging.GetLogger("", "AtomicManager"))
 	managerMap := make(map[Type]Manager)
 	managerMap[Decision] = decisionNotificationManager
 	managerMap[ProjectConfigUpdate] = projectConfigUpdateNotificationManager
 	managerMap[LogEvent] = processLogEventNotificationManager
 	managerMap[Track] = trackNotificationManager
+	managerMap[DatafileRejected] = datafileRejectedNotificationManager
 	return &DefaultCenter{
 		managerMap: managerMap,
 	}
@@ -69,6 +71,15 @@ func (c *DefaultCenter) RemoveHandler(id int, notificationType Type) error {
 	return fmt.Errorf("no notification manager found for type %s", notificationType)
 }
 
+// Clear removes the handlers of all notification types, managers which can not be cleared keep their handlers
+func (c *DefaultCenter) Clear() {
+	for _, manager := range c.managerMap {
+		if clearable, ok := manager.(interface{ Clear() }); ok {
+			clearable.Clear()
+		}
+	}
+}
+
 // Send sends the given notification payload to all listeners of type
 func (c *DefaultCenter) Send(notificationType Type, notification interface{}) error {
 	if manager, ok := c.managerMap[notificationType]; ok {
@@ -55,3 +55,24 @@ func TestNotificationCenter(t *testing.T) {
 	mockReceiver.AssertNumberOfCalls(t, "handleNotification", 1)
 	mockReceiver2.AssertNumberOfCalls(t, "handleNotification", 2)
 }
+
+func TestNotificationCenterClear(t *testing.T) {
+	notificationCenter := NewNotificationCenter()
+	calls := 0
+	for _, notificationType := range []Type{Decision, ProjectConfigUpdate, LogEvent, Track, DatafileRejected} {
+		_, err := notificationCenter.AddHandler(notificationType, func(interface{}) { calls++ })
+		assert.NoError(t, err)
+	}
+
+	notificationCenter.Clear()
+	for _, notificationType := range []Type{Decision, ProjectConfigUpdate, LogEvent, Track, DatafileRejected} {
+		assert.NoError(t, notificationCenter.Send(notificationType, nil))
+	}
+	assert.Zero(t, calls)
+
+	// handlers can be added again after clearing
+	_, err := notificationCenter.AddHandler(Decision, func(interface{}) { calls++ })
+	assert.NoError(t, err)
+	notificationCenter.Send(Decision, nil)
+	assert.Equal(t, 1, calls)
+}
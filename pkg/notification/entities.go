@@ -36,6 +36,8 @@ const (
 	ProjectConfigUpdate Type = "project_config_update"
 	// LogEvent notification type
 	LogEvent Type = "log_event_notification"
+	// DatafileRejected notification type
+	DatafileRejected Type = "datafile_rejected"
 
 	// ABTest is used when the decision is returned as part of evaluating an ab test
 	ABTest DecisionNotificationType = "ab-test"
@@ -68,8 +70,53 @@ type TrackNotification struct {
 
 // ProjectConfigUpdateNotification is a notification triggered when a project config is updated
 type ProjectConfigUpdateNotification struct {
-	Type     Type
-	Revision string
+	Type           Type
+	Revision       string
+	OldRevision    string
+	PinnedRevision string // set while the config manager is pinned to a revision
+	Diff           ConfigDiff
+}
+
+// ConfigDiff describes what changed between two revisions of the project config
+type ConfigDiff struct {
+	Flags              EntityChanges // keyed by flag key
+	Experiments        EntityChanges // keyed by experiment ID
+	Rollouts           EntityChanges // keyed by rollout ID
+	Audiences          EntityChanges // keyed by audience ID
+	TrafficAllocations []TrafficAllocationChange
+}
+
+// IsEmpty returns true if nothing changed
+func (d ConfigDiff) IsEmpty() bool {
+	return d.Flags.IsEmpty() && d.Experiments.IsEmpty() && d.Rollouts.IsEmpty() && d.Audiences.IsEmpty() && len(d.TrafficAllocations) == 0
+}
+
+// EntityChanges lists the identifiers of the entities which were added, removed or changed
+type EntityChanges struct {
+	Added   []string
+	Removed []string
+	Changed []string
+}
+
+// IsEmpty returns true if no entity was added, removed or changed
+func (c EntityChanges) IsEmpty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Changed) == 0
+}
+
+// TrafficAllocationChange describes a change of the traffic allocation of an experiment or a rollout rule
+type TrafficAllocationChange struct {
+	RuleID    string
+	RuleKey   string
+	RolloutID string // empty for experiments
+	Old       []entities.Range
+	New       []entities.Range
+}
+
+// DatafileRejectedNotification is a notification triggered when a fetched datafile is rejected and not applied
+type DatafileRejectedNotification struct {
+	Type   Type
+	Source string // the URL or path the datafile was loaded from
+	Reason string
 }
 
 // LogEventNotification is the notification triggered before log event is dispatched.
@@ -72,6 +72,14 @@ func (am *AtomicManager) Remove(id int) {
 
 }
 
+// Clear removes all the handlers
+func (am *AtomicManager) Clear() {
+	am.lock.Lock()
+	defer am.lock.Unlock()
+
+	am.handlers = make(map[uint32]func(interface{}))
+}
+
 // Send sends the notification to the registered handlers
 func (am *AtomicManager) Send(notification interface{}) {
 	// copying handler to avoid race condition
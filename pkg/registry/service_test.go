@@ -44,6 +44,17 @@ func (s *ServiceRegistryTestSuite) TestGetNotificationCenter() {
 	s.Equal(notificationCenter, notificationCenter2)
 }
 
+func (s *ServiceRegistryTestSuite) TestRemoveNotificationCenter() {
+	sdkKey := "sdk_key_removed"
+	notificationCenter := GetNotificationCenter(sdkKey)
+
+	RemoveNotificationCenter(sdkKey)
+	s.True(notificationCenter != GetNotificationCenter(sdkKey))
+
+	// removing an unknown key is a no-op
+	RemoveNotificationCenter("sdk_key_unknown")
+}
+
 func TestServiceRegistryTestSuite(t *testing.T) {
 	suite.Run(t, new(ServiceRegistryTestSuite))
 }
@@ -178,7 +178,7 @@ func (r HTTPRequester) Do(url, method string, body io.Reader, headers []Header)
 		}
 		r.logger.Debug(fmt.Sprintf("failed %s with %v", url, err))
 
-		if i != r.retries {
+		if i < r.retries-1 {
 			delay := time.Duration(500) * time.Millisecond
 			time.Sleep(delay)
 		}